@@ -0,0 +1,39 @@
+import nebulaPool from 'k6/x/nebulagraph';
+import { check } from 'k6';
+
+var graph_option = {
+	address: "192.168.8.6:10010",
+	space: "sf1",
+	csv_path: "person.csv",
+	csv_delimiter: "|",
+	csv_with_header: true,
+	max_in_flight: 16,
+	output: "output.csv"
+};
+
+nebulaPool.setOption(graph_option);
+var pool = nebulaPool.init();
+// initial session for every vu
+var session = pool.getSession()
+
+export default async function (data) {
+	// keep 16 queries in flight in one vu
+	let promises = [];
+	for (let i = 0; i < 16; i++) {
+		let d = session.getData()
+		promises.push(session.executeWithParameterAsync(
+			"FETCH PROP ON Person $id YIELD properties(vertex)",
+			{ id: parseInt(d[0]) }
+		))
+	}
+	let responses = await Promise.all(promises)
+	for (let response of responses) {
+		check(response, {
+			"IsSucceed": (r) => r !== null && r.isSucceed() === true
+		});
+	}
+};
+
+export function teardown() {
+	pool.close()
+}
@@ -6,10 +6,6 @@
 // 4. batchSize is 1, so it would insert one record per iteration.
 import nebulaPool from 'k6/x/nebulagraph';
 import { check } from 'k6';
-import { Trend } from 'k6/metrics';
-
-var latencyTrend = new Trend('latency', true);
-var responseTrend = new Trend('responseTime', true);
 
 var graph_option = {
 	address: "192.168.8.6:10010",
@@ -64,11 +60,6 @@ export default function(data) {
 	check(response, {
 		"IsSucceed": (r) => r !== null && r.isSucceed() === true
 	});
-	// add trend
-	if (response !== null) {
-		latencyTrend.add(response.getLatency() / 1000);
-		responseTrend.add(response.getResponseTime() / 1000);
-	}
 };
 
 export function teardown() {
@@ -1,11 +1,7 @@
 import nebulaPool from 'k6/x/nebulagraph';
 import { check } from 'k6';
-import { Trend } from 'k6/metrics';
 import { sleep } from 'k6';
 
-var latencyTrend = new Trend('latency', true);
-var responseTrend = new Trend('responseTime', true);
-
 var graph_option = {
 	address: "192.168.8.6:10010",
 	space: "sf1",
@@ -50,11 +46,6 @@ export default function(data) {
 	check(response, {
 		"IsSucceed": (r) => r !== null && r.isSucceed() === true
 	});
-	// add trend
-	if (response !== null) {
-		latencyTrend.add(response.getLatency() / 1000);
-		responseTrend.add(response.getResponseTime() / 1000);
-	}
 };
 
 export function teardown() {
@@ -0,0 +1,29 @@
+import nebulaPool from 'k6/x/nebulagraph';
+import { check } from 'k6';
+
+var graph_option = {
+	address: "192.168.8.6:10010",
+	space: "sf1",
+	csv_path: "person.csv",
+	csv_delimiter: "|",
+	csv_with_header: true,
+	workload_path: "workload.yaml",
+	output: "output.csv"
+};
+
+nebulaPool.setOption(graph_option);
+var pool = nebulaPool.init();
+// initial session for every vu
+var session = pool.getSession()
+
+export default function (data) {
+	// pick a template in the workload by the weights, and execute it
+	let response = session.runWorkload()
+	check(response, {
+		"IsSucceed": (r) => r !== null && r.isSucceed() === true
+	});
+};
+
+export function teardown() {
+	pool.close()
+}
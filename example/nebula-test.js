@@ -1,9 +1,5 @@
 import nebulaPool from 'k6/x/nebulagraph';
 import { check } from 'k6';
-import { Trend } from 'k6/metrics';
-
-var latencyTrend = new Trend('latency', true);
-var responseTrend = new Trend('responseTime', true);
 
 // option configuration, please refer more details in this doc.
 var graph_option = {
@@ -39,11 +35,6 @@ export default function(data) {
 	check(response, {
 		"IsSucceed": (r) => r !== null && r.isSucceed() === true
 	});
-	// add trend
-	if (response !== null) {
-		latencyTrend.add(response.getLatency() / 1000);
-		responseTrend.add(response.getResponseTime() / 1000);
-	}
 };
 
 export function teardown() {
@@ -5,20 +5,22 @@ go 1.24.0
 toolchain go1.24.11
 
 require (
+	github.com/dop251/goja v0.0.0-20230531210528-d7324b2d74f7
 	github.com/go-echarts/go-echarts/v2 v2.2.4
 	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/klauspost/compress v1.16.5
 	github.com/sirupsen/logrus v1.9.0
 	github.com/spf13/cobra v1.4.0
 	github.com/stretchr/testify v1.8.4
 	github.com/vesoft-inc/nebula-go/v3 v3.6.1
 	github.com/vesoft-inc/nebula-go/v5 v5.2.1-0.20251219041427-39b1ee6affa7
 	go.k6.io/k6 v0.45.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/dlclark/regexp2 v1.9.0 // indirect
-	github.com/dop251/goja v0.0.0-20230531210528-d7324b2d74f7 // indirect
 	github.com/fatih/color v1.15.0 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.4-0.20211119122758-180fcef48034+incompatible // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
@@ -45,5 +47,4 @@ require (
 	google.golang.org/grpc v1.66.2 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
 	gopkg.in/guregu/null.v3 v3.3.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
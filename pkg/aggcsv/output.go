@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
 	"go.k6.io/k6/output"
 )
 
@@ -53,7 +54,7 @@ func (o *Output) Start() error {
 	if err != nil {
 		return err
 	}
-	_, err = o.outputFile.Write([]byte("#timestamp,vus,requestCount,errorCount,latencyAvg,latencyP90,latencyP95,latencyP99,responseTimeAvg,responseTimeP90,responseTimeP95,responseTimeP99,rowSizePerReq\n"))
+	_, err = o.outputFile.Write([]byte("#timestamp,vus,requestCount,errorCount,latencyAvg,latencyP90,latencyP95,latencyP99,responseTimeAvg,responseTimeP90,responseTimeP95,responseTimeP99,rowSizePerReq,name\n"))
 	if err != nil {
 		return err
 	}
@@ -74,43 +75,100 @@ func (o *Output) Stop() error {
 	return nil
 }
 
+// stats the aggregation of the requests in an interval.
+type stats struct {
+	latencies    []float64
+	rts          []float64
+	requestCount int64
+	errorCount   int64
+	rowSize      int64
+}
+
 func (o *Output) aggregateAndFlush() {
 	sampleContainers := o.GetBufferedSamples()
 	if len(sampleContainers) == 0 {
 		return
 	}
 
-	var latencies []float64
-	var rts []float64
 	var vus int64
-	var requestCount int64
-	var errorCount int64
-	var rowSize int64
+	total := &stats{}
+	// named the stats of the statements with name, which are written after the total one.
+	named := make(map[string]*stats)
+
+	// the legacy metrics are defined by the scripts,
+	// they are used only if the built-in metrics are not emitted.
+	legacy := &stats{}
 
 	for _, container := range sampleContainers {
 		for _, sample := range container.GetSamples() {
 			value := sample.Value
+			statsList := []*stats{total}
+			if name, ok := sample.Tags.Get(common.TagName); ok && name != "" {
+				if _, ok := named[name]; !ok {
+					named[name] = &stats{}
+				}
+				statsList = append(statsList, named[name])
+			}
 			switch sample.Metric.Name {
 			case "vus":
 				intValue := int64(value)
 				if intValue > vus {
 					vus = intValue
 				}
+			case common.MetricReqs:
+				for _, st := range statsList {
+					st.requestCount += int64(value)
+				}
+			case common.MetricErrors:
+				for _, st := range statsList {
+					st.errorCount += int64(value)
+				}
+			case common.MetricLatency:
+				// the built-in metrics are in ms already.
+				for _, st := range statsList {
+					st.latencies = append(st.latencies, value)
+				}
+			case common.MetricResponseTime:
+				for _, st := range statsList {
+					st.rts = append(st.rts, value)
+				}
+			case common.MetricRows:
+				for _, st := range statsList {
+					st.rowSize += int64(value)
+				}
 			case "checks":
-				requestCount += 1
+				legacy.requestCount += 1
 				if int64(value) == 0 {
-					errorCount += 1
+					legacy.errorCount += 1
 				}
 			case "latency":
-				latencies = append(latencies, value/1000.0)
+				legacy.latencies = append(legacy.latencies, value/1000.0)
 			case "responseTime":
-				rts = append(rts, value/1000.0)
+				legacy.rts = append(legacy.rts, value/1000.0)
 			case "rowSize":
-				rowSize += int64(value)
+				legacy.rowSize += int64(value)
 			}
 		}
 	}
+	if total.requestCount == 0 {
+		total = legacy
+	}
+
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	_, _ = o.outputFile.Write([]byte(total.line(timestamp, vus, "")))
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = o.outputFile.Write([]byte(named[name].line(timestamp, vus, name)))
+	}
+}
 
+// line returns the csv line of the stats, the name is empty for all the requests.
+func (st *stats) line(timestamp, vus int64, name string) string {
+	latencies, rts := st.latencies, st.rts
 	sort.Slice(latencies, func(i, j int) bool {
 		return latencies[i] < latencies[j]
 	})
@@ -137,16 +195,15 @@ func (o *Output) aggregateAndFlush() {
 	}
 
 	var rowSizePerReq int64 = 0
-	if requestCount > 0 {
-		rowSizePerReq = rowSize / requestCount
+	if st.requestCount > 0 {
+		rowSizePerReq = st.rowSize / st.requestCount
 	}
 
-	line := fmt.Sprintf("%d,%d,%d,%d,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%d\n",
-		time.Now().UnixNano()/int64(time.Millisecond), vus, requestCount, errorCount,
+	return fmt.Sprintf("%d,%d,%d,%d,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%d,%s\n",
+		timestamp, vus, st.requestCount, st.errorCount,
 		latencyAvg, latencyP90, latencyP95, latencyP99,
 		rtAvg, rtP90, rtP95, rtP99,
-		rowSizePerReq)
-	_, _ = o.outputFile.Write([]byte(line))
+		rowSizePerReq, name)
 }
 
 func average(xs []float64) float64 {
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/dop251/goja"
+	"go.k6.io/k6/js/modules"
+)
+
+// InFlight limits the number of the async requests of a session.
+type InFlight chan struct{}
+
+// NewInFlight returns the limit of n requests in flight.
+func NewInFlight(n int) InFlight {
+	if n <= 0 {
+		n = 1
+	}
+	return make(InFlight, n)
+}
+
+// RunAsync runs do in a new goroutine and then done in the event loop of the vu,
+// the promise is resolved by the result of done, or rejected by the error of do or done.
+// It blocks if there are already cap(f) requests in flight, the slot is released once do returns,
+// so that the event loop is never blocked by itself.
+func (f InFlight) RunAsync(vu modules.VU, do func() error, done func() (any, error)) (*goja.Promise, error) {
+	if vu == nil || vu.State() == nil {
+		return nil, fmt.Errorf("async execution is not supported in the init context")
+	}
+	f <- struct{}{}
+	promise, resolve, reject := vu.Runtime().NewPromise()
+	callback := vu.RegisterCallback()
+	go func() {
+		err := do()
+		<-f
+		callback(func() error {
+			if err != nil {
+				reject(err)
+				return nil
+			}
+			v, err := done()
+			if err != nil {
+				reject(err)
+				return nil
+			}
+			resolve(v)
+			return nil
+		})
+	}()
+	return promise, nil
+}
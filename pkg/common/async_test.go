@@ -0,0 +1,63 @@
+package common
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/stretchr/testify/assert"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+)
+
+func TestRunAsync(t *testing.T) {
+	rt := modulestest.NewRuntime(t)
+	f := NewInFlight(2)
+	_, err := f.RunAsync(rt.VU, func() error { return nil }, func() (any, error) { return nil, nil })
+	assert.Error(t, err)
+
+	rt.MoveToVUContext(&lib.State{})
+	var (
+		running, maxRunning int32
+		promises            []*goja.Promise
+	)
+	err = rt.EventLoop.Start(func() error {
+		for i := 0; i < 6; i++ {
+			i := i
+			p, err := f.RunAsync(rt.VU, func() error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				if i == 5 {
+					return fmt.Errorf("failed")
+				}
+				return nil
+			}, func() (any, error) {
+				return i, nil
+			})
+			if err != nil {
+				return err
+			}
+			promises = append(promises, p)
+		}
+		// the unhandled rejection fails the event loop.
+		_ = rt.VU.Runtime().Set("failed", promises[5])
+		_, err := rt.VU.Runtime().RunString("failed.catch(() => {})")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	for i, p := range promises[:5] {
+		assert.Equal(t, goja.PromiseStateFulfilled, p.State())
+		assert.Equal(t, int64(i), p.Result().ToInteger())
+	}
+	assert.Equal(t, goja.PromiseStateRejected, promises[5].State())
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen the request is rejected without being sent, since the circuit breaker is open.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+const (
+	// BreakerClosed the requests are sent as usual.
+	BreakerClosed = "closed"
+	// BreakerOpen the requests are rejected with ErrBreakerOpen.
+	BreakerOpen = "open"
+	// BreakerHalfOpen one request is sent as the probe, the others are rejected until it is done.
+	BreakerHalfOpen = "half-open"
+)
+
+// Breaker the circuit breaker of a host, which opens after breaker_failures consecutive requests
+// fail without any result, e.g. the graphd is down, and lets a probe in every breaker_open_us.
+// The probe closes the breaker if it gets a result, and opens it again otherwise.
+// The nil breaker never opens.
+// If the driver does not know the host, e.g. the pools of nebula-go pick the host inside,
+// there is one breaker for the address of the pool, see Pool.getBreaker.
+type Breaker struct {
+	mutex    sync.Mutex
+	failures int
+	openTime time.Duration
+	state    string
+	count    int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker returns the breaker by opt, nil if breaker_failures is 0.
+func NewBreaker(opt BreakerOption) *Breaker {
+	if opt.BreakerFailures == 0 {
+		return nil
+	}
+	return &Breaker{
+		failures: opt.BreakerFailures,
+		openTime: time.Duration(opt.BreakerOpenUs) * time.Microsecond,
+		state:    BreakerClosed,
+	}
+}
+
+// Allow returns ErrBreakerOpen if the request should be rejected, otherwise Done must be called once it is done.
+// It returns the new state if the state changes, i.e. the request is the probe, or "".
+func (b *Breaker) Allow() (string, error) {
+	if b == nil {
+		return "", nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.openTime {
+			return "", ErrBreakerOpen
+		}
+		b.state, b.probing = BreakerHalfOpen, true
+		return b.state, nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return "", ErrBreakerOpen
+		}
+		b.probing = true
+	}
+	return "", nil
+}
+
+// Done records whether the request allowed fails without any result,
+// it returns the new state if the state changes, or "".
+func (b *Breaker) Done(failed bool) string {
+	if b == nil {
+		return ""
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+	if !failed {
+		b.count = 0
+		if b.state == BreakerClosed {
+			return ""
+		}
+		b.state = BreakerClosed
+		return b.state
+	}
+	b.count++
+	if b.state == BreakerOpen || (b.state == BreakerClosed && b.count < b.failures) {
+		return ""
+	}
+	b.state, b.openedAt = BreakerOpen, time.Now()
+	return b.state
+}
+
+// Release gives up the request allowed without recording it, e.g. it times out in the client,
+// so that the next probe could be let in.
+func (b *Breaker) Release() {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+}
+
+// State returns the current state.
+func (b *Breaker) State() string {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
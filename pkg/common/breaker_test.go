@@ -0,0 +1,53 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	var b *Breaker
+	state, err := b.Allow()
+	assert.NoError(t, err)
+	assert.Empty(t, state)
+	assert.Empty(t, b.Done(true))
+	assert.Nil(t, NewBreaker(BreakerOption{}))
+
+	b = NewBreaker(BreakerOption{BreakerFailures: 2, BreakerOpenUs: 20000})
+	assert.Equal(t, BreakerClosed, b.State())
+	_, err = b.Allow()
+	assert.NoError(t, err)
+	assert.Empty(t, b.Done(true))
+	// a success resets the consecutive failures.
+	assert.Empty(t, b.Done(false))
+	assert.Empty(t, b.Done(true))
+	assert.Equal(t, BreakerOpen, b.Done(true))
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+
+	// only one probe is let in once it has been open for breaker_open_us.
+	time.Sleep(30 * time.Millisecond)
+	state, err = b.Allow()
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerHalfOpen, state)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.Equal(t, BreakerOpen, b.Done(true))
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+
+	time.Sleep(30 * time.Millisecond)
+	state, err = b.Allow()
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerHalfOpen, state)
+	// the released probe neither closes nor opens the breaker, and the next probe is let in.
+	b.Release()
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	_, err = b.Allow()
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, b.Done(false))
+	_, err = b.Allow()
+	assert.NoError(t, err)
+}
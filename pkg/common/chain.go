@@ -0,0 +1,163 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StmtChain the statement kind of a chain in metrics.
+const StmtChain = "chain"
+
+type (
+	// ChainStep a statement in the chain, which could use the result of the previous step.
+	ChainStep struct {
+		Name string `js:"name"`
+		// Stmt the statement, the placeholders {column} are replaced by all the values
+		// in the column of the previous step, joined by comma, e.g. GO FROM {dst} OVER KNOWS.
+		Stmt   string         `js:"stmt"`
+		Params map[string]any `js:"params"`
+		// Bind the parameters bound to the previous step, keyed by the parameter name,
+		// the value is the column, whose values are bound as a list.
+		Bind map[string]string `js:"bind"`
+	}
+
+	// StepResult the result of a step in the chain.
+	StepResult struct {
+		Name         string `js:"name"`
+		Stmt         string `js:"stmt"`
+		IsSucceed    bool   `js:"isSucceed"`
+		Latency      int64  `js:"latency"`
+		ResponseTime int32  `js:"responseTime"`
+		Rows         int32  `js:"rows"`
+		ErrorMsg     string `js:"errorMsg"`
+	}
+
+	// ChainResponse the response of the chain, which is the response of the last executed step,
+	// but the latency and the response time are the total of all the steps.
+	ChainResponse struct {
+		IGraphResponse
+		Steps        []*StepResult
+		latency      int64
+		responseTime int32
+		succeed      bool
+	}
+)
+
+// NewChainResponse returns the response of the chain, last is the response of the last executed step.
+func NewChainResponse(last IGraphResponse, steps []*StepResult) *ChainResponse {
+	r := &ChainResponse{IGraphResponse: last, Steps: steps, succeed: len(steps) > 0}
+	for _, s := range steps {
+		r.latency += s.Latency
+		r.responseTime += s.ResponseTime
+		r.succeed = r.succeed && s.IsSucceed
+	}
+	return r
+}
+
+// IsSucceed returns whether all the steps succeeded.
+func (r *ChainResponse) IsSucceed() bool {
+	return r.succeed
+}
+
+// GetLatency returns the total latency of the steps in us.
+func (r *ChainResponse) GetLatency() int64 {
+	return r.latency
+}
+
+// GetResponseTime returns the total response time of the steps in us.
+func (r *ChainResponse) GetResponseTime() int32 {
+	return r.responseTime
+}
+
+// GetSteps returns the results of the executed steps.
+func (r *ChainResponse) GetSteps() []*StepResult {
+	return r.Steps
+}
+
+// Render renders the statement and the parameters by the response of the previous step,
+// prev is nil for the first step.
+func (s *ChainStep) Render(prev IGraphResponse) (string, map[string]any, error) {
+	var (
+		records []map[string]any
+		err     error
+	)
+	if prev != nil && (len(s.Bind) > 0 || placeholderRegex.MatchString(s.Stmt)) {
+		if records, err = prev.GetRecords(); err != nil {
+			return "", nil, err
+		}
+	}
+	column := func(name string) ([]any, error) {
+		if prev == nil {
+			return nil, fmt.Errorf("no previous step for column %s in step %s", name, s.Name)
+		}
+		found := false
+		for _, c := range prev.GetColumnNames() {
+			if c == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %s in step %s", name, s.Name)
+		}
+		values := make([]any, 0, len(records))
+		for _, r := range records {
+			values = append(values, r[name])
+		}
+		return values, nil
+	}
+
+	stmt := placeholderRegex.ReplaceAllStringFunc(s.Stmt, func(p string) string {
+		if err != nil {
+			return p
+		}
+		var values []any
+		if values, err = column(p[1 : len(p)-1]); err != nil {
+			return p
+		}
+		return JoinLiterals(values)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(s.Params) == 0 && len(s.Bind) == 0 {
+		return stmt, nil, nil
+	}
+	params := make(map[string]any, len(s.Params)+len(s.Bind))
+	for k, v := range s.Params {
+		params[k] = v
+	}
+	for k, c := range s.Bind {
+		values, err := column(c)
+		if err != nil {
+			return "", nil, err
+		}
+		params[k] = values
+	}
+	return stmt, params, nil
+}
+
+// JoinLiterals returns the values as the literals joined by comma, e.g. 1, "a".
+func JoinLiterals(values []any) string {
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		switch x := v.(type) {
+		case nil:
+			literals = append(literals, "NULL")
+		case string:
+			literals = append(literals, QuoteString(x))
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			literals = append(literals, fmt.Sprint(x))
+		default:
+			literals = append(literals, QuoteString(fmt.Sprint(x)))
+		}
+	}
+	return strings.Join(literals, ", ")
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// QuoteString returns the string literal in double quotes.
+func QuoteString(s string) string {
+	return `"` + stringEscaper.Replace(s) + `"`
+}
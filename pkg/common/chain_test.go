@@ -0,0 +1,64 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResponse the response with fixed records.
+type fakeResponse struct {
+	IGraphResponse
+	columns []string
+	records []map[string]any
+}
+
+func (r *fakeResponse) GetColumnNames() []string {
+	return r.columns
+}
+
+func (r *fakeResponse) GetRecords() ([]map[string]any, error) {
+	return r.records, nil
+}
+
+func TestRenderChainStep(t *testing.T) {
+	step := &ChainStep{Name: "first", Stmt: "GO FROM 933 OVER KNOWS YIELD dst(edge) AS dst"}
+	stmt, params, err := step.Render(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, step.Stmt, stmt)
+	assert.Nil(t, params)
+
+	prev := &fakeResponse{
+		columns: []string{"dst", "name"},
+		records: []map[string]any{
+			{"dst": int64(1), "name": "Tom"},
+			{"dst": int64(2), "name": `"Jerry"`},
+		},
+	}
+	step = &ChainStep{Name: "second", Stmt: "GO FROM {dst} OVER KNOWS"}
+	stmt, params, err = step.Render(prev)
+	assert.NoError(t, err)
+	assert.Equal(t, "GO FROM 1, 2 OVER KNOWS", stmt)
+	assert.Nil(t, params)
+
+	step = &ChainStep{
+		Name:   "third",
+		Stmt:   "MATCH (v:Person) WHERE v.Person.firstName IN $names AND v.Person.age > $age RETURN v",
+		Params: map[string]any{"age": 18},
+		Bind:   map[string]string{"names": "name"},
+	}
+	stmt, params, err = step.Render(prev)
+	assert.NoError(t, err)
+	assert.Equal(t, step.Stmt, stmt)
+	assert.Equal(t, map[string]any{"age": 18, "names": []any{"Tom", `"Jerry"`}}, params)
+
+	_, _, err = (&ChainStep{Name: "unknown", Stmt: "GO FROM {src} OVER KNOWS"}).Render(prev)
+	assert.Error(t, err)
+	_, _, err = (&ChainStep{Name: "first", Stmt: "GO FROM {dst} OVER KNOWS"}).Render(nil)
+	assert.Error(t, err)
+}
+
+func TestJoinLiterals(t *testing.T) {
+	assert.Equal(t, `1, 2.5, true, NULL, "a\"b\\c\n"`, JoinLiterals([]any{1, 2.5, true, nil, "a\"b\\c\n"}))
+	assert.Equal(t, "", JoinLiterals(nil))
+}
@@ -0,0 +1,676 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"go.k6.io/k6/js/modules"
+)
+
+// Client the session of a vu, which reads the data, executes the statements by the connection of the driver,
+// and reports the metrics, the expected results and the output.
+type Client struct {
+	Pool    *Pool
+	conn    Conn
+	vu      modules.VU
+	metrics *Metrics
+	logger  Logger
+	mutex   sync.Mutex
+	closed  bool
+	// lastData the data got lastly, used to find the expected result in golden file
+	// by the next request only, see takeData.
+	lastData Data
+	// rand the random source to pick the templates in workload.
+	rand *rand.Rand
+	// inFlight limits the async requests, see ExecuteAsync.
+	inFlight InFlight
+	// idle the idle connections of the async requests.
+	idle chan Conn
+	// open opens the connections of the session instead of the driver, see OpenConn.
+	open func() (Conn, error)
+}
+
+var _ IGraphClient = &Client{}
+
+func newClient(p *Pool, conn Conn, vu modules.VU, m *Metrics, l Logger) *Client {
+	inFlight := NewInFlight(p.option.MaxInFlight)
+	return &Client{
+		Pool:     p,
+		conn:     conn,
+		vu:       vu,
+		metrics:  m,
+		logger:   l,
+		inFlight: inFlight,
+		idle:     make(chan Conn, cap(inFlight)),
+	}
+}
+
+func (c *Client) Open() error {
+	return nil
+}
+
+// Close closes the connections of the session.
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	for len(c.idle) > 0 {
+		_ = (<-c.idle).Close()
+	}
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// OpenConn replaces the connection of the session by the one opened by open,
+// which opens the connections of the async requests as well, e.g. the session connects to an address by itself.
+func (c *Client) OpenConn(open func() (Conn, error)) error {
+	conn, err := open()
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		_ = conn.Close()
+		return ErrSessionClosed
+	}
+	for len(c.idle) > 0 {
+		_ = (<-c.idle).Close()
+	}
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	c.conn, c.open = conn, open
+	return nil
+}
+
+// check returns the error if the session could not be used, e.g. the pool or the session has been closed.
+func (c *Client) check() error {
+	if c == nil || c.Pool == nil {
+		return fmt.Errorf("session does not belong to a pool")
+	}
+	if err := c.Pool.lifecycle.Check(); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return ErrSessionClosed
+	}
+	return nil
+}
+
+// GetData get data from csv reader, the data source is chosen by name,
+// the default one is configured by csv_path. If the csv file has header,
+// the data are returned as an object keyed by both the column index and name, see DataSource.Value.
+// It waits csv_get_timeout_us at most, returns ErrDataEmpty if there are no data in time,
+// and ErrDataExhausted if all the data have been read.
+func (c *Client) GetData(name ...string) (any, error) {
+	source, err := c.Pool.getDataSource(name...)
+	if err != nil {
+		return nil, err
+	}
+	d, err := source.Get(c.VUContext(), c.VUID())
+	if err != nil {
+		return nil, err
+	}
+	c.lastData = d
+	return source.Value(d)
+}
+
+// TryGetData is the same as GetData, but returns ErrDataEmpty immediately if the data are not ready.
+func (c *Client) TryGetData(name ...string) (any, error) {
+	source, err := c.Pool.getDataSource(name...)
+	if err != nil {
+		return nil, err
+	}
+	d, err := source.TryGet(c.VUID())
+	if err != nil {
+		return nil, err
+	}
+	c.lastData = d
+	return source.Value(d)
+}
+
+// GetRows reads at most n rows from the data source,
+// it returns the rows read so far if the data are exhausted or not ready in time.
+// The rows read so far are returned with the other errors too, since they have been taken from the source.
+func (c *Client) GetRows(source string, n int) ([]Data, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("batch size should be greater than 0")
+	}
+	ds, err := c.Pool.getDataSource(source)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]Data, 0, n)
+	for len(rows) < n {
+		d, err := ds.Get(c.VUContext(), c.VUID())
+		if (errors.Is(err, ErrDataExhausted) || errors.Is(err, ErrDataEmpty)) && len(rows) > 0 {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, d)
+	}
+	return rows, nil
+}
+
+// RunWorkload picks a template in the workload by the weights, reads the data if the template needs,
+// and executes it, the metrics are tagged by the template name.
+func (c *Client) RunWorkload() (IGraphResponse, error) {
+	w := c.Pool.workload
+	if w == nil {
+		return nil, fmt.Errorf("no workload, please set workload_path")
+	}
+	if c.rand == nil {
+		c.rand = w.NewRand(c.VUID())
+	}
+	t := w.Pick(c.rand)
+	var data any
+	if t.NeedData() {
+		source, err := c.Pool.getDataSource(t.Source)
+		if err != nil {
+			return nil, err
+		}
+		d, err := source.Get(c.VUContext(), c.VUID())
+		if err != nil {
+			return nil, err
+		}
+		c.lastData = d
+		if data, err = source.Value(d); err != nil {
+			return nil, err
+		}
+	}
+	stmt, params, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.execute(stmt, params, &ExecuteOption{Name: t.Name, Tags: map[string]string{"template": t.Name}})
+}
+
+// VUContext returns the context of the vu, nil if there is no vu.
+func (c *Client) VUContext() context.Context {
+	if c.vu == nil {
+		return nil
+	}
+	return c.vu.Context()
+}
+
+// VUID returns the id of the vu running the client, 0 if there is no vu, e.g. in the init context.
+func (c *Client) VUID() uint64 {
+	if c.vu == nil || c.vu.State() == nil {
+		return 0
+	}
+	return c.vu.State().VUID
+}
+
+// Execute executes nebula query
+func (c *Client) Execute(stmt string, opts ...*ExecuteOption) (IGraphResponse, error) {
+	return c.execute(stmt, nil, GetExecuteOption(opts))
+}
+
+// ExecuteWithParameter executes nebula query with parameters, e.g.
+// executeWithParameter('GO FROM $id OVER KNOWS', {id: 1})
+func (c *Client) ExecuteWithParameter(stmt string, params map[string]any, opts ...*ExecuteOption) (IGraphResponse, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	return c.execute(stmt, params, GetExecuteOption(opts))
+}
+
+func (c *Client) execute(stmt string, params map[string]any, opt *ExecuteOption) (IGraphResponse, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	timeout, err := opt.timeout()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.newContext(timeout)
+	defer cancel()
+	start := time.Now()
+	data := c.takeData()
+	r, o := c.run(ctx, c.conn, stmt, params)
+	return c.report(start, stmt, data, r, o, opt), nil
+}
+
+// takeData returns the data got lastly and clears it, so that the data are used by only one request,
+// and the later requests without new data are not checked against the golden result of the data.
+func (c *Client) takeData() Data {
+	d := c.lastData
+	c.lastData = nil
+	return d
+}
+
+// newContext returns the context of a request, which is done once the timeout expires or the vu is done.
+func (c *Client) newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := c.VUContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Run executes the statement with retries, but without any report, e.g. to get the schema.
+func (c *Client) Run(stmt string, params map[string]any) (Result, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.newContext(0)
+	defer cancel()
+	r, _, err := c.executeRetry(ctx, c.conn, ProcessStmt(stmt), params)
+	return r, err
+}
+
+// ExecuteAsync executes the statement in the background, and returns a promise of the response,
+// so that a vu could keep at most max_in_flight requests in flight, e.g.
+// await Promise.all([session.executeAsync(stmt1), session.executeAsync(stmt2)])
+// It blocks if there are already max_in_flight requests in flight.
+func (c *Client) ExecuteAsync(stmt string, opts ...*ExecuteOption) (*goja.Promise, error) {
+	return c.executeAsync(stmt, nil, GetExecuteOption(opts))
+}
+
+// ExecuteWithParameterAsync is the async version of ExecuteWithParameter.
+func (c *Client) ExecuteWithParameterAsync(stmt string, params map[string]any, opts ...*ExecuteOption) (*goja.Promise, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	return c.executeAsync(stmt, params, GetExecuteOption(opts))
+}
+
+// executeAsync runs the statement on an idle connection in the background,
+// then reports it in the event loop of the vu.
+func (c *Client) executeAsync(stmt string, params map[string]any, opt *ExecuteOption) (*goja.Promise, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	timeout, err := opt.timeout()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		start time.Time
+		data  = c.takeData()
+		r     Result
+		o     *output
+	)
+	return c.inFlight.RunAsync(c.vu, func() error {
+		conn, err := c.acquireConn()
+		if err != nil {
+			return err
+		}
+		defer c.releaseConn(conn)
+		// the timeout starts once the request is sent, rather than queued.
+		ctx, cancel := c.newContext(timeout)
+		defer cancel()
+		start = time.Now()
+		r, o = c.run(ctx, conn, stmt, params)
+		return nil
+	}, func() (any, error) {
+		return c.report(start, stmt, data, r, o, opt), nil
+	})
+}
+
+// acquireConn gets an idle connection or a new one for the async request.
+func (c *Client) acquireConn() (Conn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+	c.mutex.Lock()
+	open := c.open
+	c.mutex.Unlock()
+	if open != nil {
+		return open()
+	}
+	return c.Pool.driver.Conn()
+}
+
+// releaseConn puts the connection back to the idle ones, or closes it if the session is closed.
+func (c *Client) releaseConn(conn Conn) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		_ = conn.Close()
+		return
+	}
+	select {
+	case c.idle <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
+
+// ExecuteChain executes the steps in order, the step could use the result of the previous one, see ChainStep.
+// It stops at the first failed step, or the first step which could not be rendered by the previous one,
+// and the chain is reported as one request, whose latency and response time are the total of the steps.
+func (c *Client) ExecuteChain(steps []*ChainStep, opts ...*ExecuteOption) (IGraphResponse, error) {
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps in chain")
+	}
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	opt := GetExecuteOption(opts)
+	timeout, err := opt.timeout()
+	if err != nil {
+		return nil, err
+	}
+	// the timeout is of the whole chain.
+	ctx, cancel := c.newContext(timeout)
+	defer cancel()
+	var (
+		start   = time.Now()
+		data    = c.takeData()
+		stmts   = make([]string, 0, len(steps))
+		params  = make([]string, 0, len(steps))
+		results = make([]*StepResult, 0, len(steps))
+		last    Result
+		prev    IGraphResponse
+		o       = &output{timeStamp: start.Unix(), isSucceed: true}
+	)
+	for _, step := range steps {
+		stmt, stepParams, err := step.Render(prev)
+		if err != nil {
+			// the steps executed so far are still reported, with the failed step which is not sent.
+			err = fmt.Errorf("failed to render step %s: %w", step.Name, err)
+			results = append(results, &StepResult{Name: step.Name, Stmt: step.Stmt, ErrorMsg: err.Error()})
+			last = newErrorResult(err)
+			o.rows = 0
+			o.isSucceed = false
+			o.errorMsg = err.Error()
+			break
+		}
+		r, so := c.run(ctx, c.conn, stmt, stepParams)
+		results = append(results, &StepResult{
+			Name:         step.Name,
+			Stmt:         so.nGQL,
+			IsSucceed:    so.isSucceed,
+			Latency:      so.latency,
+			ResponseTime: so.responseTime,
+			Rows:         so.rows,
+			ErrorMsg:     so.errorMsg,
+		})
+		stmts = append(stmts, so.nGQL)
+		params = append(params, so.parameters)
+		o.latency += so.latency
+		o.responseTime += so.responseTime
+		o.attempts += so.attempts
+		o.attemptTimes = append(o.attemptTimes, so.attemptTimes...)
+		if o.firstError == "" {
+			o.firstError = so.firstError
+		}
+		o.rows = so.rows
+		last = r
+		if !so.isSucceed {
+			o.isSucceed = false
+			o.isTimeout = so.isTimeout
+			o.isRejected = so.isRejected
+			o.errorMsg = so.errorMsg
+			break
+		}
+		prev = r
+	}
+	o.nGQL = strings.Join(stmts, "; ")
+	if strings.Join(params, "") != "" {
+		o.parameters = strings.Join(params, "; ")
+	}
+	result := c.report(start, StmtChain, data, last, o, opt)
+	return NewChainResponse(result, results), nil
+}
+
+// run executes the statement on conn without any report,
+// the result is never nil, the error without result is returned as a failed result.
+func (c *Client) run(ctx context.Context, conn Conn, stmt string, params map[string]any) (Result, *output) {
+	stmt = ProcessStmt(stmt)
+	start := time.Now()
+	// the template is recorded in output, and the parameters are recorded separately.
+	o := &output{timeStamp: start.Unix(), nGQL: stmt}
+	if params != nil {
+		bs, _ := json.Marshal(params)
+		o.parameters = string(bs)
+	}
+	r, attempts, err := c.executeRetry(ctx, conn, stmt, params)
+	if err != nil {
+		r = newErrorResult(err)
+		o.isTimeout = errors.Is(err, ErrTimeout)
+		o.isRejected = errors.Is(err, ErrBreakerOpen)
+	}
+	o.attempts = len(attempts)
+	o.attemptTimes = make([]int32, 0, len(attempts))
+	for _, a := range attempts {
+		o.attemptTimes = append(o.attemptTimes, a.responseTime)
+		if o.firstError == "" {
+			o.firstError = a.errorMsg
+		}
+	}
+	o.responseTime = int32(time.Since(start) / 1000)
+	r.meta().ResponseTime = o.responseTime
+	o.latency = r.GetLatency()
+	o.isSucceed = r.IsSucceed()
+	o.rows = r.GetRowSize()
+	o.errorMsg = r.GetErrorMsg()
+	return r, o
+}
+
+// executeRetry executes the statement on conn, the failed one is retried by the retry policy of the pool
+// at most retry_times times, and it stops retrying once the next retry would exceed retry_timeout_us
+// or the timeout of ctx. The failure caused by the timeout of ctx is returned as ErrTimeout.
+// Every attempt asks the circuit breaker of the host first, and the request fails with ErrBreakerOpen if rejected.
+// It returns all the attempts as well.
+func (c *Client) executeRetry(ctx context.Context, conn Conn, stmt string, params map[string]any) (Result, []attempt, error) {
+	policy := c.Pool.retry
+	var (
+		r        Result
+		err      error
+		attempts []attempt
+	)
+	start := time.Now()
+	for {
+		// the host is got for every attempt, since the connection could move to another host once it is broken.
+		host := connHost(conn)
+		breaker := c.Pool.getBreaker(host)
+		if rejected := c.allow(breaker, host); rejected != nil {
+			// the retry is given up, the last failure is returned.
+			if len(attempts) == 0 {
+				r, err = nil, rejected
+				attempts = append(attempts, attempt{errorMsg: err.Error()})
+			}
+			break
+		}
+		begin := time.Now()
+		r, err = conn.Execute(ctx, stmt, params)
+		if (err != nil || !r.IsSucceed()) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			r, err = nil, fmt.Errorf("%w after %v: %s", ErrTimeout, time.Since(start), stmt)
+		}
+		c.breakerDone(ctx, breaker, host, err)
+		a := attempt{responseTime: int32(time.Since(begin) / 1000)}
+		if err != nil {
+			a.errorMsg = err.Error()
+		} else if !r.IsSucceed() {
+			a.errorMsg = r.GetErrorMsg()
+		}
+		attempts = append(attempts, a)
+		n := len(attempts)
+		if n > policy.Times() || ctx.Err() != nil || !policy.Retryable(r, err) {
+			break
+		}
+		interval := policy.Interval(n)
+		if timeout := policy.Timeout(); timeout > 0 && time.Since(start)+interval > timeout {
+			c.logger.Warnf("retry timeout after %d attempts: %s", n, stmt)
+			break
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < interval {
+			c.logger.Warnf("no time left to retry in the statement timeout after %d attempts: %s", n, stmt)
+			break
+		}
+		c.logger.Warnf("execute statement failed, retry %d time after %v, error: %s", n, interval, a.errorMsg)
+		if !sleep(ctx, interval) {
+			break
+		}
+	}
+	return r, attempts, err
+}
+
+// connHost returns the host which the connection sends the statements to, "" if the driver does not know it.
+func connHost(conn Conn) string {
+	if h, ok := conn.(interface{ Host() string }); ok {
+		return h.Host()
+	}
+	return ""
+}
+
+// allow asks the circuit breaker of the host whether the request could be sent.
+func (c *Client) allow(b *Breaker, host string) error {
+	state, err := b.Allow()
+	c.onBreakerChange(host, state)
+	return err
+}
+
+// breakerDone tells the circuit breaker of the host whether the request fails without any result.
+// Neither the invalid parameters nor the vu being done mean the cluster is failing, so they are not counted.
+// The statement timeouts are counted as failures, unless breaker_ignore_timeouts is set.
+func (c *Client) breakerDone(ctx context.Context, b *Breaker, host string, err error) {
+	ignored := errors.Is(err, ErrTimeout) && c.Pool.option.BreakerIgnoreTimeouts
+	if ignored || errors.Is(err, ErrInvalidParams) || errors.Is(ctx.Err(), context.Canceled) {
+		b.Release()
+		return
+	}
+	c.onBreakerChange(host, b.Done(err != nil))
+}
+
+// onBreakerChange logs and reports the transition of the circuit breaker of the host to the state, if any.
+func (c *Client) onBreakerChange(host, state string) {
+	address := host
+	if address == "" {
+		address = c.Pool.option.Address
+	}
+	switch state {
+	case "":
+		return
+	case BreakerOpen:
+		c.logger.Warnf("circuit breaker of %s is open, the requests are rejected for %v",
+			address, time.Duration(c.Pool.option.BreakerOpenUs)*time.Microsecond)
+	case BreakerHalfOpen:
+		c.logger.Infof("circuit breaker of %s is half-open, probing", address)
+	case BreakerClosed:
+		c.logger.Infof("circuit breaker of %s is closed", address)
+	}
+	if c.vu == nil {
+		return
+	}
+	c.metrics.PushBreaker(c.vu.Context(), c.vu.State(), address, state)
+}
+
+// report emits the metrics, checks the expected result of data, and writes the output of a request.
+func (c *Client) report(start time.Time, rawStmt string, data Data, r Result, o *output, opt *ExecuteOption) IGraphResponse {
+	c.pushMetrics(&MetricSample{
+		Time:         start,
+		Space:        c.Pool.option.Space,
+		Stmt:         rawStmt,
+		Latency:      o.latency,
+		ResponseTime: o.responseTime,
+		Rows:         o.rows,
+		IsSucceed:    o.isSucceed,
+		IsTimeout:    o.isTimeout,
+		IsRejected:   o.isRejected,
+		Attempts:     o.attempts,
+		FirstError:   o.firstError,
+		AttemptTimes: o.attemptTimes,
+		Name:         opt.Name,
+		Tags:         opt.Tags,
+	})
+
+	expect := opt.Expect
+	if expect == nil {
+		expect = c.Pool.golden.Get(data)
+	}
+	var table [][]string
+	if o.isSucceed && (expect != nil || c.Pool.OutputCh != nil) {
+		table = r.Table()
+		r.meta().resultHash = ResultHash(table)
+		o.resultHash = r.meta().resultHash
+	}
+	if expect != nil {
+		passed, msg := false, "request failed"
+		if o.isSucceed {
+			passed, msg = expect.Check(o.rows, o.resultHash)
+		}
+		c.pushCheck(start, passed)
+		if passed {
+			o.checkResult = CheckPassed
+		} else {
+			o.checkResult = CheckFailed + ": " + msg
+		}
+	}
+	if data != nil {
+		o.dataKey = DataKey(data)
+	}
+	o.name = opt.Name
+	// the rejected requests are not written, which are all the same while the breaker is open.
+	if c.Pool.OutputCh != nil && !o.isRejected {
+		if len(table) != 0 {
+			// print the first row of the result
+			o.firstRecord = strings.Join(table[0], "|")
+		}
+		select {
+		case c.Pool.OutputCh <- formatOutput(o):
+		// abandon if the output chan is full.
+		default:
+			c.logger.Warnf("output channel is full, abandon the output: %v", o)
+		}
+	}
+	return r
+}
+
+// pushMetrics emits the metrics of a request to the vu, if the client is bound to one.
+func (c *Client) pushMetrics(s *MetricSample) {
+	if c.vu == nil {
+		return
+	}
+	c.metrics.Push(c.vu.Context(), c.vu.State(), s)
+}
+
+// pushCheck emits the result of checking the expected result to the vu.
+func (c *Client) pushCheck(t time.Time, passed bool) {
+	if c.vu == nil {
+		return
+	}
+	c.metrics.PushCheck(c.vu.Context(), c.vu.State(), t, passed)
+}
+
+// PushInserted emits the number of the inserted rows of the tag or the edge type to the vu.
+func (c *Client) PushInserted(schema string, rows int) {
+	if c.vu == nil {
+		return
+	}
+	c.metrics.PushInserted(c.vu.Context(), c.vu.State(), schema, rows)
+}
+
+// SkipRow logs the row which could not be inserted as the tag or the edge type,
+// and emits it as a skipped row to the vu.
+func (c *Client) SkipRow(schema string, row Data, err error) {
+	c.logger.Warnf("skip the row %v of %s: %s", row, schema, err)
+	if c.vu == nil {
+		return
+	}
+	c.metrics.PushSkipped(c.vu.Context(), c.vu.State(), schema, 1)
+}
@@ -1,9 +1,19 @@
 package common
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
 	"encoding/csv"
+	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 type (
@@ -11,7 +21,20 @@ type (
 		Path       string
 		Delimiter  string
 		WithHeader bool
-		limit      int
+		Mode       FeedMode
+		// Seed the seed to shuffle or sample the data, 0 means a random seed.
+		Seed int64
+		// Sample the options in zipfian, hotspot and latest mode.
+		Sample SampleOption
+		// Streaming reads the rows from disk lazily instead of loading them in memory,
+		// the limit is ignored in streaming.
+		Streaming bool
+		// Logger logs the error which stops the streaming, if not nil.
+		Logger Logger
+		limit  int
+		header []string
+		mutex  sync.Mutex
+		err    error
 	}
 
 	CSVWriter struct {
@@ -22,11 +45,16 @@ type (
 	}
 )
 
-func NewCsvReader(path, delimiter string, withHeader bool, limit int) *CSVReader {
+func NewCsvReader(path, delimiter string, withHeader bool, limit int, mode FeedMode, seed int64) *CSVReader {
+	if mode == "" {
+		mode = FeedCycle
+	}
 	return &CSVReader{
 		Path:       path,
 		Delimiter:  delimiter,
 		WithHeader: withHeader,
+		Mode:       mode,
+		Seed:       seed,
 		limit:      limit,
 	}
 }
@@ -40,34 +68,134 @@ func NewCsvWriter(path, delimiter string, header []string, dataCh <-chan []strin
 	}
 }
 
-// ReadForever read the csv in slice first, and send to the data channel forever.
+// Header returns the header of the csv file, which is read once feeding.
+func (c *CSVReader) Header() []string {
+	return c.header
+}
+
+// Deprecated ReadForever read the csv in slice first, and send to the data channel forever.
 func (c *CSVReader) ReadForever(dataCh chan<- Data) error {
-	lines := make([]Data, 0, c.limit)
-	file, err := os.Open(c.Path)
+	return c.Feed(context.Background(), []chan<- Data{dataCh})
+}
+
+// Feed reads the csv in slice first, or reads lazily in streaming, and sends to the data channels according to the mode,
+// until ctx is done. In partition mode, the data are split into len(dataChs) partitions,
+// otherwise only the first channel is used.
+// The channels are closed once all the data are sent, i.e. the data are exhausted.
+func (c *CSVReader) Feed(ctx context.Context, dataChs []chan<- Data) error {
+	if c.Streaming {
+		switch c.Mode {
+		case FeedShuffle, FeedZipfian, FeedHotspot, FeedLatest:
+			return fmt.Errorf("%s mode is not supported in streaming", c.Mode)
+		}
+		if c.Mode != FeedPartition {
+			dataChs = dataChs[:1]
+		}
+		// every partition has its own reader, so that a partition which is not read never blocks the others.
+		readers := make([]*csv.Reader, 0, len(dataChs))
+		closeFns := make([]func(), 0, len(dataChs))
+		for range dataChs {
+			reader, closeFn, err := c.open()
+			if err != nil {
+				for _, fn := range closeFns {
+					fn()
+				}
+				return err
+			}
+			readers = append(readers, reader)
+			closeFns = append(closeFns, closeFn)
+		}
+		for i, ch := range dataChs {
+			go c.stream(ctx, ch, i, len(dataChs), readers[i], closeFns[i])
+		}
+		return nil
+	}
+	lines, err := c.read()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = file.Close()
-	}()
-	reader := csv.NewReader(file)
-	comma := []rune(c.Delimiter)
-	if len(comma) > 0 {
-		reader.Comma = comma[0]
-	}
-	if c.WithHeader {
-		_, err := reader.Read()
+	switch c.Mode {
+	case FeedCycle:
+		go feed(ctx, lines, dataChs[0], true, nil)
+	case FeedOnce:
+		go feed(ctx, lines, dataChs[0], false, nil)
+	case FeedShuffle:
+		seed := c.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		go feed(ctx, lines, dataChs[0], true, rand.New(rand.NewSource(seed)))
+	case FeedZipfian, FeedHotspot, FeedLatest:
+		seed := c.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		next, err := newSampler(c.Mode, len(lines), rand.New(rand.NewSource(seed)), c.Sample)
 		if err != nil {
 			return err
 		}
+		go sample(ctx, lines, dataChs[0], next)
+	case FeedPartition:
+		partitions := make([][]Data, len(dataChs))
+		for i, line := range lines {
+			partitions[i%len(dataChs)] = append(partitions[i%len(dataChs)], line)
+		}
+		for i, ch := range dataChs {
+			go feed(ctx, partitions[i], ch, true, nil)
+		}
+	default:
+		return fmt.Errorf("invalid feed mode: %s", c.Mode)
+	}
+	return nil
+}
+
+// feed sends the lines to the channel, shuffles the lines in every round if rnd is not nil.
+func feed(ctx context.Context, lines []Data, dataCh chan<- Data, cycle bool, rnd *rand.Rand) {
+	for {
+		if rnd != nil {
+			rnd.Shuffle(len(lines), func(i, j int) {
+				lines[i], lines[j] = lines[j], lines[i]
+			})
+		}
+		for _, line := range lines {
+			select {
+			case dataCh <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if !cycle || len(lines) == 0 {
+			close(dataCh)
+			return
+		}
+	}
+}
+
+// sample sends the lines picked by next to the channel until ctx is done.
+func sample(ctx context.Context, lines []Data, dataCh chan<- Data, next func() int) {
+	for {
+		select {
+		case dataCh <- lines[next()]:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *CSVReader) read() ([]Data, error) {
+	lines := make([]Data, 0, c.limit)
+	reader, closeFn, err := c.open()
+	if err != nil {
+		return nil, err
 	}
+	defer closeFn()
 	for {
 		row, err := reader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		lines = append(lines, row)
@@ -75,19 +203,130 @@ func (c *CSVReader) ReadForever(dataCh chan<- Data) error {
 			break
 		}
 	}
+	return lines, nil
+}
 
-	go func() {
-		index := 0
-		for {
-			if index == len(lines) {
-				index = 0
-			}
-			dataCh <- lines[index]
-			index++
+// stream reads the rows from disk lazily, the reading blocks until the channel has room.
+// It starts over at the end in cycle mode, and closes the channel at the end in once mode.
+// Only the row n where n % partitions == partition is sent to the channel.
+// The reader stops and closes the channel on error, which is returned by Err then.
+func (c *CSVReader) stream(
+	ctx context.Context,
+	dataCh chan<- Data,
+	partition, partitions int,
+	reader *csv.Reader,
+	closeFn func(),
+) {
+	defer func() {
+		if closeFn != nil {
+			closeFn()
 		}
 	}()
-	return nil
+	for {
+		n := 0
+		for ; ; n++ {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				c.fail(err)
+				close(dataCh)
+				return
+			}
+			if n%partitions != partition {
+				continue
+			}
+			select {
+			case dataCh <- row:
+			case <-ctx.Done():
+				return
+			}
+		}
+		closeFn()
+		closeFn = nil
+		if c.Mode == FeedOnce || n <= partition {
+			close(dataCh)
+			return
+		}
+		var err error
+		if reader, closeFn, err = c.open(); err != nil {
+			c.fail(err)
+			close(dataCh)
+			return
+		}
+	}
+}
+
+// fail records and logs the error which stops the streaming.
+func (c *CSVReader) fail(err error) {
+	err = fmt.Errorf("failed to stream %s: %w", c.Path, err)
+	if c.Logger != nil {
+		c.Logger.Errorf("%s", err.Error())
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.err = err
+}
 
+// Err returns the error which stops the streaming, nil if the data are exhausted without any error.
+func (c *CSVReader) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.err
+}
+
+// open opens the csv file and skips the header, the file is decompressed by the extension,
+// .gz for gzip and .zst for zstd.
+func (c *CSVReader) open() (*csv.Reader, func(), error) {
+	file, err := os.Open(c.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		r       io.Reader = file
+		closeFn           = func() { _ = file.Close() }
+	)
+	switch {
+	case strings.HasSuffix(c.Path, ".gz"):
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		r = gr
+		closeFn = func() {
+			_ = gr.Close()
+			_ = file.Close()
+		}
+	case strings.HasSuffix(c.Path, ".zst"):
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		r = zr
+		closeFn = func() {
+			zr.Close()
+			_ = file.Close()
+		}
+	}
+	reader := csv.NewReader(bufio.NewReader(r))
+	comma := []rune(c.Delimiter)
+	if len(comma) > 0 {
+		reader.Comma = comma[0]
+	}
+	if c.WithHeader {
+		header, err := reader.Read()
+		if err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		if c.header == nil {
+			c.header = header
+		}
+	}
+	return reader, closeFn, nil
 }
 
 func (c *CSVWriter) WriteForever() error {
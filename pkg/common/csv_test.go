@@ -0,0 +1,143 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeCsv(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("id\n1\n2\n3\n4\n"), 0o644))
+	return path
+}
+
+func TestFeedOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan Data, 10)
+	r := NewCsvReader(writeCsv(t), ",", true, 100, FeedOnce, 0)
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch}))
+	var got []string
+	for d := range ch {
+		got = append(got, d[0])
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, got)
+}
+
+func TestFeedPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch1, ch2 := make(chan Data), make(chan Data)
+	r := NewCsvReader(writeCsv(t), ",", true, 100, FeedPartition, 0)
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch1, ch2}))
+	for _, want := range []string{"1", "3", "1"} {
+		assert.Equal(t, want, (<-ch1)[0])
+	}
+	for _, want := range []string{"2", "4", "2"} {
+		assert.Equal(t, want, (<-ch2)[0])
+	}
+}
+
+func TestFeedShuffle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan Data)
+	r := NewCsvReader(writeCsv(t), ",", true, 100, FeedShuffle, 1)
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch}))
+	got := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		got[(<-ch)[0]]++
+	}
+	assert.Equal(t, map[string]int{"1": 2, "2": 2, "3": 2, "4": 2}, got)
+}
+
+func TestFeedStreaming(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte("id\n1\n2\n3\n"))
+	assert.NoError(t, w.Close())
+	path := filepath.Join(t.TempDir(), "data.csv.gz")
+	assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan Data)
+	r := NewCsvReader(path, ",", true, 1, FeedCycle, 0)
+	r.Streaming = true
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch}))
+	for _, want := range []string{"1", "2", "3", "1", "2"} {
+		assert.Equal(t, want, (<-ch)[0])
+	}
+
+	ch = make(chan Data)
+	r = NewCsvReader(path, ",", true, 1, FeedOnce, 0)
+	r.Streaming = true
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch}))
+	var got []string
+	for d := range ch {
+		got = append(got, d[0])
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, got)
+}
+
+func TestFeedStreamingPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// ch1 is never read, which should not block ch2.
+	ch1, ch2 := make(chan Data), make(chan Data)
+	r := NewCsvReader(writeCsv(t), ",", true, 100, FeedPartition, 0)
+	r.Streaming = true
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch1, ch2}))
+	for _, want := range []string{"2", "4", "2", "4", "2"} {
+		select {
+		case d := <-ch2:
+			assert.Equal(t, want, d[0])
+		case <-time.After(time.Second):
+			t.Fatal("partition 2 is blocked by partition 1")
+		}
+	}
+	assert.Equal(t, "1", (<-ch1)[0])
+}
+
+func TestFeedStreamingError(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte("id\n1\n2\n3\n"))
+	assert.NoError(t, w.Close())
+	path := filepath.Join(t.TempDir(), "data.csv.gz")
+	// the corrupt file is not told from the end of the data by the channel, but by Err.
+	assert.NoError(t, os.WriteFile(path, buf.Bytes()[:buf.Len()-4], 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan Data)
+	r := NewCsvReader(path, ",", true, 1, FeedOnce, 0)
+	r.Streaming = true
+	assert.NoError(t, r.Feed(ctx, []chan<- Data{ch}))
+	for range ch {
+	}
+	assert.Error(t, r.Err())
+
+	path = filepath.Join(t.TempDir(), "data.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("id\n1\n2,3\n"), 0o644))
+	ds, err := NewDataSource(ctx, "person", &DataSourceOption{CsvOption: CsvOption{
+		CsvPath: path, CsvDelimiter: ",", CsvWithHeader: true, CsvFeedMode: string(FeedOnce),
+		CsvStreaming: true, CsvGetTimeoutUs: 1000000,
+	}}, nil)
+	assert.NoError(t, err)
+	d, err := ds.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, Data{"1"}, d)
+	_, err = ds.Get(ctx, 1)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDataExhausted)
+	_, err = ds.TryGet(1)
+	assert.NotErrorIs(t, err, ErrDataExhausted)
+}
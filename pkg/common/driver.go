@@ -0,0 +1,161 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidParams the parameters could not be converted by the driver, which is never retried.
+	ErrInvalidParams = errors.New("invalid parameters")
+	// ErrTimeout the statement is not done in the timeout of the statement, which is never retried.
+	ErrTimeout = errors.New("statement timeout")
+)
+
+type (
+	// Driver the version specific part of the pool, which is implemented by nebulagraph for 3.x
+	// and nebulagraph5 for 5.x, the rest, e.g. the data, the metrics and the output, is done by Pool and Client.
+	Driver interface {
+		// Open opens the connection pool or the session pool to the hosts by the option.
+		Open(opt *GraphOption, hosts []HostAddress, l Logger) error
+		// Conn returns a connection for a session, which is used by one goroutine at a time.
+		Conn() (Conn, error)
+		// RetryOn returns the errors retried if retry_on is not set, see RetryPolicy.
+		RetryOn() []string
+		// Wrap returns the session exposed to js, which could add the version specific methods to the client.
+		Wrap(c *Client) IGraphClient
+		// Close closes the connections opened by Open.
+		Close() error
+	}
+
+	// Conn executes the statements of a session.
+	// The connection which sends the statements to one host at a time could implement Host() string as well,
+	// which returns the host, so that the circuit breaker is per host, see Pool.getBreaker.
+	Conn interface {
+		// Execute executes the statement with the parameters once, params is nil if there are no parameters.
+		// The failed statement is returned as the result, and the error means there is no result at all,
+		// e.g. the connection is broken. It should return once ctx is done, with the error of ctx.
+		Execute(ctx context.Context, stmt string, params map[string]any) (Result, error)
+		// Close releases the connection.
+		Close() error
+	}
+
+	// Result the result of a statement returned by the driver, which should embed ResultMeta.
+	Result interface {
+		IGraphResponse
+		// Table returns all the rows as strings, which are used in the result hash and the output.
+		Table() [][]string
+		meta() *ResultMeta
+	}
+
+	// ResultMeta the fields of the result set by the client.
+	ResultMeta struct {
+		ResponseTime int32
+		resultHash   string
+	}
+
+	// HostAddress the address of a graphd.
+	HostAddress struct {
+		Host string
+		Port int
+	}
+
+	// Logger the logger of the pool and the client.
+	Logger interface {
+		Debugf(format string, args ...any)
+		Infof(format string, args ...any)
+		Warnf(format string, args ...any)
+		Errorf(format string, args ...any)
+	}
+
+	// errorResult the result of the request which failed without any result, e.g. the connection is broken.
+	errorResult struct {
+		ResultMeta
+		err error
+	}
+)
+
+var _ Result = &errorResult{}
+
+// GetResponseTime returns the response time in the client, in us.
+func (m *ResultMeta) GetResponseTime() int32 {
+	return m.ResponseTime
+}
+
+// GetResultHash returns the hash of all the rows, which is set only if it is used.
+func (m *ResultMeta) GetResultHash() string {
+	return m.resultHash
+}
+
+func (m *ResultMeta) meta() *ResultMeta {
+	return m
+}
+
+// ParseAddress parses the address like 192.168.8.6:9669,192.168.8.7:9669.
+func ParseAddress(address string) ([]HostAddress, error) {
+	var hosts []HostAddress
+	for _, addr := range strings.Split(address, ",") {
+		host, port, err := net.SplitHostPort(strings.TrimSpace(addr))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address: %s", addr)
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address: %s", addr)
+		}
+		hosts = append(hosts, HostAddress{Host: host, Port: p})
+	}
+	return hosts, nil
+}
+
+func (h HostAddress) String() string {
+	return net.JoinHostPort(h.Host, strconv.Itoa(h.Port))
+}
+
+func newErrorResult(err error) *errorResult {
+	return &errorResult{err: err}
+}
+
+func (r *errorResult) IsSucceed() bool {
+	return false
+}
+
+func (r *errorResult) GetLatency() int64 {
+	return 0
+}
+
+func (r *errorResult) GetRowSize() int32 {
+	return 0
+}
+
+func (r *errorResult) GetErrorCode() string {
+	return ""
+}
+
+func (r *errorResult) GetErrorMsg() string {
+	return r.err.Error()
+}
+
+func (r *errorResult) GetColumnNames() []string {
+	return nil
+}
+
+func (r *errorResult) GetRows() ([][]any, error) {
+	return nil, nil
+}
+
+func (r *errorResult) GetRow(index int) ([]any, error) {
+	return nil, fmt.Errorf("row index out of range: %d", index)
+}
+
+func (r *errorResult) GetRecords() ([]map[string]any, error) {
+	return nil, nil
+}
+
+func (r *errorResult) Table() [][]string {
+	return nil
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	CheckPassed = "passed"
+	CheckFailed = "failed"
+)
+
+type (
+	// Expect the expected result of a statement, the empty fields are not checked.
+	Expect struct {
+		Rows *int   `js:"rows"`
+		Hash string `js:"hash"`
+	}
+
+	// Golden the expected results keyed by the csv data, e.g.
+	//
+	//	key,rows,hash
+	//	933,10,
+	//	4194,,3c1a0e...
+	Golden map[string]*Expect
+)
+
+// Check checks the result, returns the reason if it does not match.
+func (e *Expect) Check(rows int32, hash string) (bool, string) {
+	if e.Rows != nil && int32(*e.Rows) != rows {
+		return false, fmt.Sprintf("expect %d rows, but got %d", *e.Rows, rows)
+	}
+	if e.Hash != "" && e.Hash != hash {
+		return false, fmt.Sprintf("expect hash %s, but got %s", e.Hash, hash)
+	}
+	return true, ""
+}
+
+// ResultHash returns the hash of the rows, which does not depend on the order of the rows,
+// since the order is not stable without ORDER BY.
+func ResultHash(rows [][]string) string {
+	hashes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		h := sha256.Sum256([]byte(strings.Join(row, "\x1f")))
+		hashes = append(hashes, hex.EncodeToString(h[:]))
+	}
+	sort.Strings(hashes)
+	h := sha256.Sum256([]byte(strings.Join(hashes, "")))
+	return hex.EncodeToString(h[:])
+}
+
+// DataKey returns the key of the csv data in golden file.
+func DataKey(d Data) string {
+	return strings.Join(d, ",")
+}
+
+// LoadGolden loads the golden file, which has the header key,rows,hash.
+func LoadGolden(path string) (Golden, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+	if _, err := reader.Read(); err != nil {
+		return nil, err
+	}
+	golden := make(Golden)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := &Expect{Hash: row[2]}
+		if row[1] != "" {
+			rows, err := strconv.Atoi(row[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid rows in golden file: %s", row[1])
+			}
+			e.Rows = &rows
+		}
+		golden[row[0]] = e
+	}
+	return golden, nil
+}
+
+// Get returns the expected result of the csv data, nil if not found.
+func (g Golden) Get(d Data) *Expect {
+	if g == nil || d == nil {
+		return nil
+	}
+	return g[DataKey(d)]
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultHash(t *testing.T) {
+	h1 := ResultHash([][]string{{"1", "a"}, {"2", "b"}})
+	h2 := ResultHash([][]string{{"2", "b"}, {"1", "a"}})
+	assert.Equal(t, h1, h2)
+	assert.NotEqual(t, h1, ResultHash([][]string{{"1", "a"}}))
+	assert.NotEqual(t, ResultHash([][]string{{"1a"}}), ResultHash([][]string{{"1", "a"}}))
+}
+
+func TestExpectCheck(t *testing.T) {
+	rows := 2
+	e := &Expect{Rows: &rows}
+	ok, _ := e.Check(2, "")
+	assert.True(t, ok)
+	ok, msg := e.Check(3, "")
+	assert.False(t, ok)
+	assert.Equal(t, "expect 2 rows, but got 3", msg)
+
+	e = &Expect{Hash: "abc"}
+	ok, _ = e.Check(3, "abc")
+	assert.True(t, ok)
+	ok, _ = e.Check(3, "abd")
+	assert.False(t, ok)
+}
+
+func TestLoadGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("key,rows,hash\n933,10,\n\"4194,a\",,abc\n"), 0644))
+	golden, err := LoadGolden(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, *golden.Get(Data{"933"}).Rows)
+	assert.Equal(t, "abc", golden.Get(Data{"4194", "a"}).Hash)
+	assert.Nil(t, golden.Get(Data{"1"}))
+	assert.Nil(t, Golden(nil).Get(Data{"933"}))
+}
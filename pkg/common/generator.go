@@ -0,0 +1,217 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	// GenSequence generates min, min+1, ..., max, and starts over at the end, there is no end if max is 0.
+	GenSequence GeneratorType = "sequence"
+	// GenUniform generates the integers in [min, max] uniformly.
+	GenUniform GeneratorType = "uniform"
+	// GenZipfian generates the integers in [min, max], the smaller ones are more frequent.
+	GenZipfian GeneratorType = "zipfian"
+	// GenString generates the random strings in length.
+	GenString GeneratorType = "string"
+	// GenTimestamp generates the time in [min, max] seconds uniformly.
+	GenTimestamp GeneratorType = "timestamp"
+	// GenEnum picks one of the values uniformly.
+	GenEnum GeneratorType = "enum"
+)
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+type (
+	// GeneratorType the type of the generated column
+	GeneratorType string
+
+	// ColumnGenerator the spec of a generated column.
+	ColumnGenerator struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Min  int64  `json:"min"`
+		Max  int64  `json:"max"`
+		// Length the length of the string.
+		Length int `json:"length,omitempty"`
+		// Skew the skew of zipfian, which should be greater than 1, 1.1 by default.
+		Skew float64 `json:"skew,omitempty"`
+		// Format the format of timestamp, unix, date or datetime, unix by default.
+		Format string   `json:"format,omitempty"`
+		Values []string `json:"values,omitempty"`
+	}
+
+	// Generator generates the data on the fly instead of reading a csv file.
+	Generator struct {
+		Columns []*ColumnGenerator
+		Mode    FeedMode
+		// Seed the seed of the random values, 0 means a random seed.
+		Seed int64
+		// limit the number of rows generated in once mode.
+		limit int
+	}
+
+	// columnState the state of a column while generating.
+	columnState struct {
+		spec *ColumnGenerator
+		next int64
+		// step the step of the sequence, which is the number of partitions.
+		step int64
+		zipf *rand.Zipf
+	}
+)
+
+func NewGenerator(columns []*ColumnGenerator, limit int, mode FeedMode, seed int64) *Generator {
+	if mode == "" {
+		mode = FeedCycle
+	}
+	return &Generator{
+		Columns: columns,
+		Mode:    mode,
+		Seed:    seed,
+		limit:   limit,
+	}
+}
+
+// Header returns the names of the columns.
+func (g *Generator) Header() []string {
+	header := make([]string, 0, len(g.Columns))
+	for _, c := range g.Columns {
+		header = append(header, c.Name)
+	}
+	return header
+}
+
+// Feed generates the rows and sends to the data channels until ctx is done.
+// In once mode, it generates limit rows then closes the channels,
+// in partition mode, the row n is sent to the channel n % len(dataChs).
+// Every channel is fed by its own goroutine, so that a partition which is not read never blocks the others.
+func (g *Generator) Feed(ctx context.Context, dataChs []chan<- Data) error {
+	switch g.Mode {
+	case FeedCycle, FeedOnce:
+		dataChs = dataChs[:1]
+	case FeedPartition:
+	default:
+		return fmt.Errorf("feed mode %s is not supported by generator", g.Mode)
+	}
+	seed := g.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	feeds := make([]func(), 0, len(dataChs))
+	for i, ch := range dataChs {
+		// the partition i has its own random source, which is still the same for the same seed.
+		r := rand.New(rand.NewSource(seed + int64(i)))
+		states := make([]*columnState, 0, len(g.Columns))
+		for _, c := range g.Columns {
+			s, err := newColumnState(c, r, i, len(dataChs))
+			if err != nil {
+				return err
+			}
+			states = append(states, s)
+		}
+		i, ch := i, ch
+		feeds = append(feeds, func() {
+			for n := i; g.Mode != FeedOnce || n < g.limit; n += len(dataChs) {
+				row := make(Data, 0, len(states))
+				for _, s := range states {
+					row = append(row, s.generate(r))
+				}
+				select {
+				case ch <- row:
+				case <-ctx.Done():
+					return
+				}
+			}
+			close(ch)
+		})
+	}
+	for _, f := range feeds {
+		go f()
+	}
+	return nil
+}
+
+// newColumnState returns the state of the column in the partition,
+// whose sequence starts from min+partition and goes by the step of partitions.
+func newColumnState(c *ColumnGenerator, r *rand.Rand, partition, partitions int) (*columnState, error) {
+	s := &columnState{spec: c, next: c.Min, step: int64(partitions)}
+	switch GeneratorType(c.Type) {
+	case GenSequence:
+		if c.Max != 0 && c.Max < c.Min {
+			return nil, fmt.Errorf("max of column %s is less than min", c.Name)
+		}
+		s.next = s.wrap(c.Min + int64(partition))
+	case GenUniform, GenTimestamp:
+		if c.Max < c.Min {
+			return nil, fmt.Errorf("max of column %s is less than min", c.Name)
+		}
+	case GenZipfian:
+		if c.Max < c.Min {
+			return nil, fmt.Errorf("max of column %s is less than min", c.Name)
+		}
+		skew := c.Skew
+		if skew == 0 {
+			skew = 1.1
+		}
+		if skew <= 1 {
+			return nil, fmt.Errorf("skew of column %s should be greater than 1", c.Name)
+		}
+		s.zipf = rand.NewZipf(r, skew, 1, uint64(c.Max-c.Min))
+	case GenString:
+		if c.Length <= 0 {
+			return nil, fmt.Errorf("length of column %s should be greater than 0", c.Name)
+		}
+	case GenEnum:
+		if len(c.Values) == 0 {
+			return nil, fmt.Errorf("values of column %s is empty", c.Name)
+		}
+	default:
+		return nil, fmt.Errorf("invalid type of column %s: %s", c.Name, c.Type)
+	}
+	return s, nil
+}
+
+func (s *columnState) generate(r *rand.Rand) string {
+	c := s.spec
+	switch GeneratorType(c.Type) {
+	case GenSequence:
+		v := s.next
+		s.next = s.wrap(s.next + s.step)
+		return strconv.FormatInt(v, 10)
+	case GenUniform:
+		return strconv.FormatInt(c.Min+r.Int63n(c.Max-c.Min+1), 10)
+	case GenZipfian:
+		return strconv.FormatInt(c.Min+int64(s.zipf.Uint64()), 10)
+	case GenString:
+		b := make([]byte, c.Length)
+		for i := range b {
+			b[i] = letters[r.Intn(len(letters))]
+		}
+		return string(b)
+	case GenTimestamp:
+		t := time.Unix(c.Min+r.Int63n(c.Max-c.Min+1), 0).UTC()
+		switch c.Format {
+		case "date":
+			return t.Format("2006-01-02")
+		case "datetime":
+			return t.Format("2006-01-02T15:04:05")
+		default:
+			return strconv.FormatInt(t.Unix(), 10)
+		}
+	default:
+		return c.Values[r.Intn(len(c.Values))]
+	}
+}
+
+// wrap starts the sequence over from min if next is beyond max.
+func (s *columnState) wrap(next int64) int64 {
+	c := s.spec
+	if c.Max == 0 || next <= c.Max {
+		return next
+	}
+	return c.Min + (next-c.Min)%(c.Max-c.Min+1)
+}
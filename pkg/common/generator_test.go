@@ -0,0 +1,75 @@
+package common
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generate(t *testing.T, g *Generator, n int) []Data {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan Data)
+	assert.NoError(t, g.Feed(ctx, []chan<- Data{ch}))
+	rows := make([]Data, 0, n)
+	for d := range ch {
+		rows = append(rows, d)
+		if len(rows) == n {
+			break
+		}
+	}
+	return rows
+}
+
+func TestGenerator(t *testing.T) {
+	columns := []*ColumnGenerator{
+		{Name: "id", Type: "sequence", Min: 1, Max: 3},
+		{Name: "friend", Type: "zipfian", Min: 1, Max: 100},
+		{Name: "name", Type: "string", Length: 8},
+		{Name: "gender", Type: "enum", Values: []string{"male", "female"}},
+		{Name: "birthday", Type: "timestamp", Min: 0, Max: 86400 * 365, Format: "date"},
+	}
+	g := NewGenerator(columns, 100, FeedCycle, 1)
+	assert.Equal(t, []string{"id", "friend", "name", "gender", "birthday"}, g.Header())
+	rows := generate(t, g, 4)
+	for i, want := range []string{"1", "2", "3", "1"} {
+		assert.Equal(t, want, rows[i][0])
+	}
+	for _, row := range rows {
+		friend, err := strconv.Atoi(row[1])
+		assert.NoError(t, err)
+		assert.True(t, friend >= 1 && friend <= 100)
+		assert.Len(t, row[2], 8)
+		assert.Contains(t, []string{"male", "female"}, row[3])
+		assert.Len(t, row[4], len("1970-01-01"))
+	}
+	// the same seed generates the same data
+	assert.Equal(t, rows, generate(t, NewGenerator(columns, 100, FeedCycle, 1), 4))
+
+	rows = generate(t, NewGenerator(columns[:1], 2, FeedOnce, 1), 10)
+	assert.Len(t, rows, 2)
+
+	g = NewGenerator([]*ColumnGenerator{{Name: "id", Type: "unknown"}}, 2, FeedCycle, 1)
+	assert.Error(t, g.Feed(context.Background(), []chan<- Data{make(chan Data)}))
+}
+
+func TestGeneratorPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// ch1 is never read, which should not block ch2.
+	ch1, ch2 := make(chan Data), make(chan Data)
+	g := NewGenerator([]*ColumnGenerator{{Name: "id", Type: "sequence", Min: 1, Max: 5}}, 100, FeedPartition, 1)
+	assert.NoError(t, g.Feed(ctx, []chan<- Data{ch1, ch2}))
+	for _, want := range []string{"2", "4", "1", "3", "5", "2"} {
+		select {
+		case d := <-ch2:
+			assert.Equal(t, want, d[0])
+		case <-time.After(time.Second):
+			t.Fatal("partition 2 is blocked by partition 1")
+		}
+	}
+	assert.Equal(t, "1", (<-ch1)[0])
+}
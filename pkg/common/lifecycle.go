@@ -0,0 +1,138 @@
+package common
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	// PoolNew the pool is created, but the option is not set.
+	PoolNew PoolState = iota
+	// PoolConfigured the option is set, but the pool is not initialized.
+	PoolConfigured
+	// PoolInitialized the pool is ready to use.
+	PoolInitialized
+	// PoolClosing the pool is releasing the sessions and the connections.
+	PoolClosing
+	// PoolClosed the pool could not be used any more.
+	PoolClosed
+)
+
+var (
+	ErrPoolNotConfigured  = errors.New("pool is not configured, please call setOption first")
+	ErrPoolNotInitialized = errors.New("pool is not initialized, please call init first")
+	ErrPoolClosed         = errors.New("pool has been closed")
+	ErrSessionClosed      = errors.New("session has been closed")
+)
+
+type (
+	// PoolState the state of the pool, see Lifecycle.
+	PoolState int32
+
+	// Lifecycle the state machine of the pool, new → configured → initialized → closing → closed,
+	// which is shared by the nebulagraph and nebulagraph5 pools and is safe for concurrent use.
+	Lifecycle struct {
+		mutex sync.Mutex
+		state PoolState
+	}
+)
+
+func (s PoolState) String() string {
+	switch s {
+	case PoolNew:
+		return "new"
+	case PoolConfigured:
+		return "configured"
+	case PoolInitialized:
+		return "initialized"
+	case PoolClosing:
+		return "closing"
+	case PoolClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// State returns the current state.
+func (l *Lifecycle) State() PoolState {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.state
+}
+
+// Configure moves the pool from new to configured if configure succeeds.
+// It does nothing if the pool has been configured, since all the vus set the same option.
+func (l *Lifecycle) Configure(configure func() error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	switch l.state {
+	case PoolNew:
+		if err := configure(); err != nil {
+			return err
+		}
+		l.state = PoolConfigured
+		return nil
+	case PoolConfigured, PoolInitialized:
+		return nil
+	default:
+		return ErrPoolClosed
+	}
+}
+
+// Init moves the pool from configured to initialized if init succeeds, otherwise the pool stays configured,
+// so init should release what it has created on failure. It does nothing if the pool has been initialized.
+func (l *Lifecycle) Init(init func() error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	switch l.state {
+	case PoolNew:
+		return ErrPoolNotConfigured
+	case PoolConfigured:
+		if err := init(); err != nil {
+			return err
+		}
+		l.state = PoolInitialized
+		return nil
+	case PoolInitialized:
+		return nil
+	default:
+		return ErrPoolClosed
+	}
+}
+
+// Close moves the pool to closed, release is called only if the pool has been initialized.
+// It does nothing if the pool is closing or closed.
+func (l *Lifecycle) Close(release func() error) error {
+	l.mutex.Lock()
+	state := l.state
+	if state == PoolClosing || state == PoolClosed {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.state = PoolClosing
+	l.mutex.Unlock()
+
+	var err error
+	if state == PoolInitialized {
+		err = release()
+	}
+	l.mutex.Lock()
+	l.state = PoolClosed
+	l.mutex.Unlock()
+	return err
+}
+
+// Check returns the error if the pool is not ready to use.
+func (l *Lifecycle) Check() error {
+	switch l.State() {
+	case PoolNew:
+		return ErrPoolNotConfigured
+	case PoolConfigured:
+		return ErrPoolNotInitialized
+	case PoolInitialized:
+		return nil
+	default:
+		return ErrPoolClosed
+	}
+}
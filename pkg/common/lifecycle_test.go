@@ -0,0 +1,54 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle(t *testing.T) {
+	var (
+		l        Lifecycle
+		inits    int
+		releases int
+	)
+	init := func() error {
+		inits++
+		return nil
+	}
+	release := func() error {
+		releases++
+		return nil
+	}
+	assert.Equal(t, PoolNew, l.State())
+	assert.ErrorIs(t, l.Check(), ErrPoolNotConfigured)
+	assert.ErrorIs(t, l.Init(init), ErrPoolNotConfigured)
+
+	assert.Error(t, l.Configure(func() error { return fmt.Errorf("invalid option") }))
+	assert.Equal(t, PoolNew, l.State())
+	assert.NoError(t, l.Configure(func() error { return nil }))
+	assert.NoError(t, l.Configure(func() error { return fmt.Errorf("configured twice") }))
+	assert.ErrorIs(t, l.Check(), ErrPoolNotInitialized)
+
+	assert.Error(t, l.Init(func() error { return fmt.Errorf("failed to connect") }))
+	assert.Equal(t, PoolConfigured, l.State())
+	assert.NoError(t, l.Init(init))
+	assert.NoError(t, l.Init(init))
+	assert.Equal(t, 1, inits)
+	assert.NoError(t, l.Check())
+
+	assert.NoError(t, l.Close(release))
+	assert.NoError(t, l.Close(release))
+	assert.Equal(t, 1, releases)
+	assert.Equal(t, PoolClosed, l.State())
+	assert.ErrorIs(t, l.Check(), ErrPoolClosed)
+	assert.ErrorIs(t, l.Init(init), ErrPoolClosed)
+	assert.ErrorIs(t, l.Configure(func() error { return nil }), ErrPoolClosed)
+
+	// the pool which is never initialized has nothing to release.
+	var l2 Lifecycle
+	assert.NoError(t, l2.Close(release))
+	assert.Equal(t, 1, releases)
+	assert.Equal(t, "closed", l2.State().String())
+}
@@ -0,0 +1,253 @@
+package common
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+const (
+	MetricLatency      = "nebula_latency"
+	MetricResponseTime = "nebula_response_time"
+	MetricRows         = "nebula_rows"
+	MetricReqs         = "nebula_reqs"
+	MetricErrors       = "nebula_errors"
+	MetricInsertedRows = "nebula_inserted_rows"
+	MetricSkippedRows  = "nebula_skipped_rows"
+	MetricAttempts     = "nebula_attempts"
+	MetricRetries      = "nebula_retries"
+	MetricAttemptTime  = "nebula_attempt_time"
+	MetricTimeouts     = "nebula_timeouts"
+	MetricRejected     = "nebula_rejected"
+	MetricBreaker      = "nebula_breaker_transitions"
+
+	// TagName the tag of the statement name.
+	TagName = "name"
+
+	// CheckResult the name of the check for the expected result.
+	CheckResult = "nebula result"
+)
+
+type (
+	// Metrics the built-in k6 metrics emitted by graph clients.
+	Metrics struct {
+		Latency      *metrics.Metric
+		ResponseTime *metrics.Metric
+		Rows         *metrics.Metric
+		Reqs         *metrics.Metric
+		Errors       *metrics.Metric
+		InsertedRows *metrics.Metric
+		SkippedRows  *metrics.Metric
+		Attempts     *metrics.Metric
+		Retries      *metrics.Metric
+		AttemptTime  *metrics.Metric
+		Timeouts     *metrics.Metric
+		Rejected     *metrics.Metric
+		Breaker      *metrics.Metric
+	}
+
+	// MetricSample the measurement of one request.
+	MetricSample struct {
+		Time time.Time
+		// Space the graph space the request runs in.
+		Space string
+		Stmt  string
+		// Latency the server side latency in us.
+		Latency int64
+		// ResponseTime the client side response time in us.
+		ResponseTime int32
+		Rows         int32
+		IsSucceed    bool
+		// IsTimeout the request fails because of the statement timeout.
+		IsTimeout bool
+		// IsRejected the request is rejected by the circuit breaker without being sent.
+		IsRejected bool
+		// Attempts the number of the attempts, including the retries, 0 is taken as 1.
+		Attempts int
+		// FirstError the error of the first failed attempt, added as the metadata first_error.
+		FirstError string
+		// AttemptTimes the response time of every attempt in us.
+		AttemptTimes []int32
+		// Name the name of the statement, tagged as name if not empty.
+		Name string
+		// Tags the extra tags, e.g. the template name in workload.
+		Tags map[string]string
+	}
+)
+
+// RegisterMetrics registers the built-in metrics in registry,
+// registering the same metric twice returns the existing one.
+func RegisterMetrics(registry *metrics.Registry) (*Metrics, error) {
+	var (
+		m   = &Metrics{}
+		err error
+	)
+	if m.Latency, err = registry.NewMetric(MetricLatency, metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+	if m.ResponseTime, err = registry.NewMetric(MetricResponseTime, metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+	if m.Rows, err = registry.NewMetric(MetricRows, metrics.Trend); err != nil {
+		return nil, err
+	}
+	if m.Reqs, err = registry.NewMetric(MetricReqs, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.Errors, err = registry.NewMetric(MetricErrors, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.InsertedRows, err = registry.NewMetric(MetricInsertedRows, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.SkippedRows, err = registry.NewMetric(MetricSkippedRows, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.Attempts, err = registry.NewMetric(MetricAttempts, metrics.Trend); err != nil {
+		return nil, err
+	}
+	if m.Retries, err = registry.NewMetric(MetricRetries, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.AttemptTime, err = registry.NewMetric(MetricAttemptTime, metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+	if m.Timeouts, err = registry.NewMetric(MetricTimeouts, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.Rejected, err = registry.NewMetric(MetricRejected, metrics.Counter); err != nil {
+		return nil, err
+	}
+	if m.Breaker, err = registry.NewMetric(MetricBreaker, metrics.Counter); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Push sends the samples of one request to the vu's sample channel.
+// It does nothing in the init context, where there is no vu state.
+func (m *Metrics) Push(ctx context.Context, state *lib.State, s *MetricSample) {
+	if m == nil || state == nil || ctx == nil {
+		return
+	}
+	tags := state.Tags.GetCurrentValues().Tags.
+		With("space", s.Space).
+		With("kind", StmtKind(s.Stmt)).
+		With("success", strconv.FormatBool(s.IsSucceed))
+	if s.Name != "" {
+		tags = tags.With(TagName, s.Name)
+	}
+	for k, v := range s.Tags {
+		tags = tags.With(k, v)
+	}
+	var errors, timeouts, rejected float64
+	if !s.IsSucceed {
+		errors = 1
+	}
+	if s.IsTimeout {
+		timeouts = 1
+	}
+	if s.IsRejected {
+		rejected = 1
+	}
+	attempts := s.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	samples := []metrics.Sample{
+		newSample(m.Latency, tags, s.Time, float64(s.Latency)/1000),
+		newSample(m.ResponseTime, tags, s.Time, float64(s.ResponseTime)/1000),
+		newSample(m.Rows, tags, s.Time, float64(s.Rows)),
+		newSample(m.Reqs, tags, s.Time, 1),
+		newSample(m.Errors, tags, s.Time, errors),
+		newSample(m.Timeouts, tags, s.Time, timeouts),
+		newSample(m.Rejected, tags, s.Time, rejected),
+		newSample(m.Attempts, tags, s.Time, float64(attempts)),
+		newSample(m.Retries, tags, s.Time, float64(attempts-1)),
+	}
+	if s.FirstError != "" {
+		// the error message is of high cardinality, so it is the metadata rather than a tag.
+		for i := len(samples) - 2; i < len(samples); i++ {
+			samples[i].Metadata = map[string]string{"first_error": s.FirstError}
+		}
+	}
+	for _, t := range s.AttemptTimes {
+		samples = append(samples, newSample(m.AttemptTime, tags, s.Time, float64(t)/1000))
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.ConnectedSamples{
+		Samples: samples,
+		Tags:    tags,
+		Time:    s.Time,
+	})
+}
+
+// PushCheck sends the result of checking the expected result to the builtin checks metric.
+func (m *Metrics) PushCheck(ctx context.Context, state *lib.State, t time.Time, passed bool) {
+	if state == nil || ctx == nil {
+		return
+	}
+	tags := state.Tags.GetCurrentValues().Tags.With("check", CheckResult)
+	var value float64
+	if passed {
+		value = 1
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, newSample(state.BuiltinMetrics.Checks, tags, t, value))
+}
+
+// PushBreaker sends the transition of the circuit breaker of the host, or the address of the pool, to the state.
+func (m *Metrics) PushBreaker(ctx context.Context, state *lib.State, address, to string) {
+	if m == nil || state == nil || ctx == nil {
+		return
+	}
+	tags := state.Tags.GetCurrentValues().Tags.With("address", address).With("state", to)
+	metrics.PushIfNotDone(ctx, state.Samples, newSample(m.Breaker, tags, time.Now(), 1))
+}
+
+// PushInserted sends the number of the rows inserted in batch, tagged by the tag or edge type.
+func (m *Metrics) PushInserted(ctx context.Context, state *lib.State, schema string, rows int) {
+	if m == nil {
+		return
+	}
+	m.pushRows(ctx, state, m.InsertedRows, schema, rows)
+}
+
+// PushSkipped sends the number of the rows skipped in batch insert, tagged by the tag or edge type.
+func (m *Metrics) PushSkipped(ctx context.Context, state *lib.State, schema string, rows int) {
+	if m == nil {
+		return
+	}
+	m.pushRows(ctx, state, m.SkippedRows, schema, rows)
+}
+
+func (m *Metrics) pushRows(ctx context.Context, state *lib.State, metric *metrics.Metric, schema string, rows int) {
+	if state == nil || ctx == nil {
+		return
+	}
+	tags := state.Tags.GetCurrentValues().Tags.With("schema", schema)
+	metrics.PushIfNotDone(ctx, state.Samples, newSample(metric, tags, time.Now(), float64(rows)))
+}
+
+func newSample(metric *metrics.Metric, tags *metrics.TagSet, t time.Time, value float64) metrics.Sample {
+	return metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   tags,
+		},
+		Time:  t,
+		Value: value,
+	}
+}
+
+// StmtKind returns the kind of the statement in lower case, i.e. the first keyword,
+// e.g. go, match, insert.
+func StmtKind(stmt string) string {
+	fields := strings.Fields(stmt)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(fields[0], ";"))
+}
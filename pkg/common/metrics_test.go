@@ -0,0 +1,14 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtKind(t *testing.T) {
+	assert.Equal(t, "go", StmtKind("GO 2 STEPS FROM 1 OVER KNOWS"))
+	assert.Equal(t, "match", StmtKind("  match (v) return v"))
+	assert.Equal(t, "show", StmtKind("show;"))
+	assert.Equal(t, "", StmtKind(""))
+}
@@ -0,0 +1,230 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.k6.io/k6/js/modules"
+)
+
+var _ modules.Module = &Module{}
+
+// refer: https://k6.io/docs/extensions/get-started/create/javascript-extensions/#use-the-advanced-module-api
+// Module is a module for k6, using the advanced module API, the pools connect by the drivers created by newDriver.
+type Module struct {
+	newDriver func() Driver
+	// pool is the default pool, configured by setOption.
+	pool *Pool
+	// pools are created by newPool, keyed by the option.
+	pools map[string]*Pool
+	mutex sync.Mutex
+}
+
+// ModuleInstance is the module instance of a vu, it holds the state of the vu,
+// and the sessions got from it are released when the vu is done.
+type ModuleInstance struct {
+	vu      modules.VU
+	module  *Module
+	pool    *vuPool
+	metrics *Metrics
+	logger  Logger
+	mutex   sync.Mutex
+	clients []*Client
+	closed  bool
+}
+
+// vuPool is the view of a shared pool in a vu.
+type vuPool struct {
+	instance *ModuleInstance
+	pool     *Pool
+}
+
+var _ IGraphClientPool = &ModuleInstance{}
+var _ IGraphClientPool = &vuPool{}
+
+type loggerWrapper struct {
+	log logrus.FieldLogger
+}
+
+func (l *loggerWrapper) Debugf(msg string, args ...any) {
+	l.log.Debugf(msg, args...)
+}
+
+func (l *loggerWrapper) Infof(msg string, args ...any) {
+	l.log.Infof(msg, args...)
+}
+
+func (l *loggerWrapper) Warnf(msg string, args ...any) {
+	l.log.Warnf(msg, args...)
+}
+
+func (l *loggerWrapper) Errorf(msg string, args ...any) {
+	l.log.Errorf(msg, args...)
+}
+
+// NewModule returns the module whose pools connect by the drivers created by newDriver.
+func NewModule(newDriver func() Driver) *Module {
+	return &Module{
+		newDriver: newDriver,
+		pool:      NewPool(newDriver()),
+		pools:     make(map[string]*Pool),
+	}
+}
+
+// getPool gets the pool for option, the pools with the same option are shared by all the vus.
+func (m *Module) getPool(option *GraphOption, l Logger) (*Pool, error) {
+	option = MakeDefaultOption(option)
+	if err := ValidateOption(option); err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(option)
+	if err != nil {
+		return nil, err
+	}
+	key := string(bs)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if p, ok := m.pools[key]; ok {
+		return p, nil
+	}
+	p := NewPool(m.newDriver())
+	p.setLogger(l)
+	if err := p.SetOption(option); err != nil {
+		return nil, err
+	}
+	m.pools[key] = p
+	return p, nil
+}
+
+// NewModuleInstance is the constructor of the vu state, it is called once per vu.
+func (m *Module) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := RegisterMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		panic(err)
+	}
+	l := &loggerWrapper{log: vu.InitEnv().Logger}
+	m.pool.setLogger(l)
+	i := &ModuleInstance{
+		vu:      vu,
+		module:  m,
+		metrics: metrics,
+		logger:  l,
+	}
+	i.pool = &vuPool{instance: i, pool: m.pool}
+	// the context in init stage lives as long as the vu.
+	i.releaseOnDone(vu.Context())
+	return i
+}
+
+func (i *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Default: i,
+	}
+}
+
+// releaseOnDone releases the sessions of the vu once ctx is done.
+func (i *ModuleInstance) releaseOnDone(ctx context.Context) {
+	if ctx == nil || ctx.Done() == nil {
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		i.release()
+	}()
+}
+
+func (i *ModuleInstance) release() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, c := range i.clients {
+		_ = c.Close()
+	}
+	i.clients = nil
+	i.closed = true
+}
+
+// getSession gets a session of p which belongs to the vu.
+func (i *ModuleInstance) getSession(p *Pool) (*Client, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.closed {
+		return nil, fmt.Errorf("vu is done")
+	}
+	c, err := p.getSession(i.vu, i.metrics, i.logger)
+	if err != nil {
+		return nil, err
+	}
+	i.clients = append(i.clients, c)
+	return c, nil
+}
+
+// NewPool creates a pool with its own option, csv data and output,
+// which is independent of the default pool and the other pools.
+func (i *ModuleInstance) NewPool(option *GraphOption) (IGraphClientPool, error) {
+	p, err := i.module.getPool(option, i.logger)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Init(); err != nil {
+		return nil, err
+	}
+	return &vuPool{instance: i, pool: p}, nil
+}
+
+func (i *ModuleInstance) SetOption(option *GraphOption) error {
+	return i.pool.SetOption(option)
+}
+
+// Init initializes the default pool.
+func (i *ModuleInstance) Init() (IGraphClientPool, error) {
+	return i.pool.Init()
+}
+
+// GetSession gets a session of the default pool.
+func (i *ModuleInstance) GetSession() (IGraphClient, error) {
+	return i.pool.GetSession()
+}
+
+// Close closes the default pool.
+func (i *ModuleInstance) Close() error {
+	return i.pool.Close()
+}
+
+// Deprecated ConfigCsvStrategy sets csv reader strategy
+func (i *ModuleInstance) ConfigCsvStrategy(strategy int) {
+	i.pool.ConfigCsvStrategy(strategy)
+}
+
+func (p *vuPool) SetOption(option *GraphOption) error {
+	return p.pool.SetOption(option)
+}
+
+// Init initializes the shared pool.
+func (p *vuPool) Init() (IGraphClientPool, error) {
+	if _, err := p.pool.Init(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetSession gets a session which belongs to the vu.
+func (p *vuPool) GetSession() (IGraphClient, error) {
+	c, err := p.instance.getSession(p.pool)
+	if err != nil {
+		return nil, err
+	}
+	return p.pool.driver.Wrap(c), nil
+}
+
+// Close closes the shared pool.
+func (p *vuPool) Close() error {
+	return p.pool.Close()
+}
+
+// Deprecated ConfigCsvStrategy sets csv reader strategy
+func (p *vuPool) ConfigCsvStrategy(strategy int) {
+	p.pool.ConfigCsvStrategy(strategy)
+}
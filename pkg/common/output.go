@@ -0,0 +1,85 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OutputHeader the header of the output file.
+var OutputHeader = []string{
+	"timestamp",
+	"nGQL",
+	"latency",
+	"responseTime",
+	"isSucceed",
+	"rows",
+	"firstRecord",
+	"errorMsg",
+	"parameters",
+	"resultHash",
+	"dataKey",
+	"checkResult",
+	"name",
+	"attempts",
+	"firstError",
+	"attemptTimes",
+	"isTimeout",
+}
+
+// output a line in the output file.
+type output struct {
+	timeStamp    int64
+	nGQL         string
+	latency      int64
+	responseTime int32
+	isSucceed    bool
+	// isTimeout the request fails because of the statement timeout, rather than the server.
+	isTimeout bool
+	// isRejected the request is rejected by the circuit breaker, which is not written.
+	isRejected  bool
+	rows        int32
+	errorMsg    string
+	firstRecord string
+	parameters  string
+	resultHash  string
+	dataKey     string
+	checkResult string
+	name        string
+	// attempts the number of the attempts, including the retries.
+	attempts int
+	// firstError the error of the first failed attempt, which may be hidden by a successful retry.
+	firstError string
+	// attemptTimes the response time of every attempt in us.
+	attemptTimes []int32
+}
+
+func formatOutput(o *output) []string {
+	return []string{
+		strconv.FormatInt(o.timeStamp, 10),
+		o.nGQL,
+		strconv.Itoa(int(o.latency)),
+		strconv.Itoa(int(o.responseTime)),
+		strconv.FormatBool(o.isSucceed),
+		strconv.Itoa(int(o.rows)),
+		o.firstRecord,
+		o.errorMsg,
+		o.parameters,
+		o.resultHash,
+		o.dataKey,
+		o.checkResult,
+		o.name,
+		strconv.Itoa(o.attempts),
+		o.firstError,
+		formatTimes(o.attemptTimes),
+		strconv.FormatBool(o.isTimeout),
+	}
+}
+
+// formatTimes joins the times by |, e.g. 1200|800.
+func formatTimes(times []int32) string {
+	ss := make([]string, 0, len(times))
+	for _, t := range times {
+		ss = append(ss, strconv.Itoa(int(t)))
+	}
+	return strings.Join(ss, "|")
+}
@@ -0,0 +1,240 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// Pool the pool shared by the vus, which connects to the NebulaGraph by the driver,
+// and feeds the data, writes the output, and loads the golden file and the workload.
+type Pool struct {
+	// DataCh the channel of the default data source.
+	DataCh    chan Data
+	OutputCh  chan []string
+	driver    Driver
+	lifecycle Lifecycle
+	mutex     sync.Mutex
+	option    *GraphOption
+	logger    Logger
+	sources   map[string]*DataSource
+	cancel    context.CancelFunc
+	golden    Golden
+	workload  *Workload
+	retry     *RetryPolicy
+	// breakers the circuit breakers keyed by host, see getBreaker.
+	breakers map[string]*Breaker
+	clients  []*Client
+}
+
+var _ IGraphClientPool = &Pool{}
+
+// NewPool returns a pool which connects by the driver.
+func NewPool(driver Driver) *Pool {
+	return &Pool{driver: driver}
+}
+
+// Option returns the option of the pool, nil if the pool is not configured.
+func (p *Pool) Option() *GraphOption {
+	return p.option
+}
+
+// setLogger sets the logger of the pool if it is not set yet.
+func (p *Pool) setLogger(l Logger) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.logger == nil {
+		p.logger = l
+	}
+}
+
+// SetOption sets the option of the pool, the option could be set only once.
+func (p *Pool) SetOption(option *GraphOption) error {
+	return p.lifecycle.Configure(func() error {
+		if option == nil {
+			return fmt.Errorf("option is empty")
+		}
+		opt := MakeDefaultOption(option)
+		if err := ValidateOption(opt); err != nil {
+			return err
+		}
+		p.option = opt
+		bs, _ := json.Marshal(p.option)
+		p.logger.Debugf("testing option: %s", bs)
+		return nil
+	})
+}
+
+// Init initializes the pool, it could be called more than once.
+func (p *Pool) Init() (IGraphClientPool, error) {
+	err := p.lifecycle.Init(func() error {
+		if err := p.init(); err != nil {
+			_ = p.release()
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// init opens the driver, the output writer, the data sources, the golden file and the workload.
+func (p *Pool) init() error {
+	p.logger.Debugf("initializing graph pool")
+	hosts, err := ParseAddress(p.option.Address)
+	if err != nil {
+		return err
+	}
+	if err := p.driver.Open(p.option, hosts, p.logger); err != nil {
+		return err
+	}
+	p.retry = NewRetryPolicy(p.option.RetryOption, p.driver.RetryOn())
+	p.breakers = make(map[string]*Breaker)
+	if p.option.Output != "" {
+		p.OutputCh = make(chan []string, p.option.OutputChannelSize)
+		writer := NewCsvWriter(p.option.Output, ",", OutputHeader, p.OutputCh)
+		if err := writer.WriteForever(); err != nil {
+			return err
+		}
+	}
+	if err := p.initDataSources(); err != nil {
+		return err
+	}
+	if p.option.GoldenPath != "" {
+		golden, err := LoadGolden(p.option.GoldenPath)
+		if err != nil {
+			return err
+		}
+		p.golden = golden
+	}
+	if p.option.WorkloadPath != "" {
+		workload, err := LoadWorkload(p.option.WorkloadPath)
+		if err != nil {
+			return err
+		}
+		p.workload = workload
+	}
+	return nil
+}
+
+// initDataSources starts to feed the data of csv_path or generator and data_sources until the pool is closed.
+func (p *Pool) initDataSources() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	sources := make(map[string]*DataSource, len(p.option.DataSources)+1)
+	if p.option.CsvPath != "" || len(p.option.Generator) > 0 {
+		opt := &DataSourceOption{
+			CsvOption:       p.option.CsvOption,
+			GeneratorOption: p.option.GeneratorOption,
+		}
+		source, err := NewDataSource(ctx, DefaultDataSource, opt, p.logger)
+		if err != nil {
+			cancel()
+			return err
+		}
+		sources[DefaultDataSource] = source
+		p.DataCh = source.Chan(0)
+	}
+	for name, opt := range p.option.DataSources {
+		source, err := NewDataSource(ctx, name, opt, p.logger)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("data source %s: %w", name, err)
+		}
+		sources[name] = source
+	}
+	p.sources = sources
+	p.cancel = cancel
+	return nil
+}
+
+// getDataSource returns the data source by name, or the default one if no name.
+func (p *Pool) getDataSource(name ...string) (*DataSource, error) {
+	if err := p.lifecycle.Check(); err != nil {
+		return nil, err
+	}
+	n := DefaultDataSource
+	if len(name) > 0 {
+		n = name[0]
+	}
+	source, ok := p.sources[n]
+	if !ok {
+		if n == DefaultDataSource {
+			return nil, ErrDataEmpty
+		}
+		return nil, fmt.Errorf("no data source: %s", n)
+	}
+	return source, nil
+}
+
+// Deprecated ConfigCsvStrategy sets csv reader strategy
+func (p *Pool) ConfigCsvStrategy(strategy int) {
+}
+
+// Close closes the pool, the sessions got from it could not be used any more.
+func (p *Pool) Close() error {
+	return p.lifecycle.Close(p.release)
+}
+
+// release stops the data sources, and closes the sessions and the connections opened so far.
+func (p *Pool) release() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	for _, c := range p.clients {
+		_ = c.Close()
+	}
+	p.clients = nil
+	return p.driver.Close()
+}
+
+// getBreaker returns the circuit breaker of the host, which is created on the first request to the host.
+// The host is "" if the driver does not know it, e.g. nebula-go balances the hosts inside its pool,
+// and the breaker of the address of the pool is returned then. It returns nil if there is no breaker.
+func (p *Pool) getBreaker(host string) *Breaker {
+	if p.option.BreakerFailures == 0 {
+		return nil
+	}
+	if host == "" {
+		host = p.option.Address
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	b, ok := p.breakers[host]
+	if !ok {
+		b = NewBreaker(p.option.BreakerOption)
+		p.breakers[host] = b
+	}
+	return b
+}
+
+// GetSession gets the session from pool
+func (p *Pool) GetSession() (IGraphClient, error) {
+	c, err := p.getSession(nil, nil, p.logger)
+	if err != nil {
+		return nil, err
+	}
+	return p.driver.Wrap(c), nil
+}
+
+// getSession gets the session from pool, the session would emit metrics to the vu.
+func (p *Pool) getSession(vu modules.VU, m *Metrics, l Logger) (*Client, error) {
+	if err := p.lifecycle.Check(); err != nil {
+		return nil, err
+	}
+	conn, err := p.driver.Conn()
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(p, conn, vu, m, l)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.clients = append(p.clients, c)
+	return c, nil
+}
@@ -0,0 +1,438 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// fakeDriver returns the results in order, and the last one for the rest.
+	fakeDriver struct {
+		opened  bool
+		openErr error
+		results []Result
+		conns   int
+		closed  int
+		// executed the number of the statements executed, including the retries.
+		executed int
+		// delay the time of executing a statement.
+		delay time.Duration
+		// hosts the hosts of the connections in turn, the connections have no host if it is empty.
+		hosts []string
+		// down the hosts which refuse the connections.
+		down map[string]bool
+	}
+
+	fakeConn struct {
+		driver *fakeDriver
+		host   string
+	}
+
+	fakeResult struct {
+		ResultMeta
+		errorCode string
+		errorMsg  string
+		table     [][]string
+	}
+)
+
+func (d *fakeDriver) Open(opt *GraphOption, hosts []HostAddress, l Logger) error {
+	d.opened = d.openErr == nil
+	return d.openErr
+}
+
+func (d *fakeDriver) Conn() (Conn, error) {
+	c := &fakeConn{driver: d}
+	if len(d.hosts) > 0 {
+		c.host = d.hosts[d.conns%len(d.hosts)]
+	}
+	d.conns++
+	return c, nil
+}
+
+func (d *fakeDriver) RetryOn() []string {
+	return []string{RetryNetwork, "E_EXECUTION_ERROR"}
+}
+
+func (d *fakeDriver) Wrap(c *Client) IGraphClient {
+	return c
+}
+
+func (d *fakeDriver) Close() error {
+	d.opened = false
+	return nil
+}
+
+func (c *fakeConn) Execute(ctx context.Context, stmt string, params map[string]any) (Result, error) {
+	if _, ok := params["invalid"]; ok {
+		return nil, fmt.Errorf("%w: invalid", ErrInvalidParams)
+	}
+	d := c.driver
+	d.executed++
+	if d.delay > 0 && !sleep(ctx, d.delay) {
+		return nil, ctx.Err()
+	}
+	if len(d.results) == 0 || d.down[c.host] {
+		return nil, fmt.Errorf("connection refused")
+	}
+	r := d.results[0]
+	if len(d.results) > 1 {
+		d.results = d.results[1:]
+	}
+	return r, nil
+}
+
+func (c *fakeConn) Host() string {
+	return c.host
+}
+
+func (c *fakeConn) Close() error {
+	c.driver.closed++
+	return nil
+}
+
+func (r *fakeResult) IsSucceed() bool                       { return r.errorMsg == "" }
+func (r *fakeResult) GetLatency() int64                     { return 10 }
+func (r *fakeResult) GetRowSize() int32                     { return int32(len(r.table)) }
+func (r *fakeResult) GetErrorCode() string                  { return r.errorCode }
+func (r *fakeResult) GetErrorMsg() string                   { return r.errorMsg }
+func (r *fakeResult) GetColumnNames() []string              { return nil }
+func (r *fakeResult) GetRows() ([][]any, error)             { return nil, nil }
+func (r *fakeResult) GetRow(index int) ([]any, error)       { return nil, nil }
+func (r *fakeResult) GetRecords() ([]map[string]any, error) { return nil, nil }
+func (r *fakeResult) Table() [][]string                     { return r.table }
+
+func newFakePool(t *testing.T, d *fakeDriver, opt *GraphOption) *Pool {
+	p := NewPool(d)
+	p.setLogger(&loggerWrapper{log: logrus.New()})
+	assert.NoError(t, p.SetOption(opt))
+	return p
+}
+
+func TestPoolLifecycle(t *testing.T) {
+	d := &fakeDriver{openErr: fmt.Errorf("connection refused")}
+	p := NewPool(d)
+	p.setLogger(&loggerWrapper{log: logrus.New()})
+	_, err := p.GetSession()
+	assert.ErrorIs(t, err, ErrPoolNotConfigured)
+	_, err = p.Init()
+	assert.ErrorIs(t, err, ErrPoolNotConfigured)
+
+	assert.Error(t, p.SetOption(nil))
+	assert.NoError(t, p.SetOption(&GraphOption{PoolOption: PoolOption{Address: "127.0.0.1:9669", Space: "sf1"}}))
+	_, err = p.GetSession()
+	assert.ErrorIs(t, err, ErrPoolNotInitialized)
+	_, err = p.Init()
+	assert.Error(t, err)
+	_, err = p.getDataSource()
+	assert.ErrorIs(t, err, ErrPoolNotInitialized)
+
+	d.openErr = nil
+	_, err = p.Init()
+	assert.NoError(t, err)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+	assert.True(t, d.opened)
+
+	assert.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+	assert.False(t, d.opened)
+	assert.Equal(t, 1, d.closed)
+	_, err = p.GetSession()
+	assert.ErrorIs(t, err, ErrPoolClosed)
+	_, err = p.Init()
+	assert.ErrorIs(t, err, ErrPoolClosed)
+	_, err = s.Execute("RETURN 1")
+	assert.ErrorIs(t, err, ErrPoolClosed)
+	_, err = s.GetData()
+	assert.ErrorIs(t, err, ErrPoolClosed)
+
+	// the invalid address fails the init.
+	p = newFakePool(t, &fakeDriver{}, &GraphOption{PoolOption: PoolOption{Address: "127.0.0.1", Space: "sf1"}})
+	_, err = p.Init()
+	assert.Error(t, err)
+}
+
+func TestClientExecute(t *testing.T) {
+	d := &fakeDriver{results: []Result{
+		&fakeResult{errorCode: "E_EXECUTION_ERROR", errorMsg: "execution error"},
+		&fakeResult{table: [][]string{{"1", "a"}, {"2", "b"}}},
+		&fakeResult{errorCode: "E_SEMANTIC_ERROR", errorMsg: "semantic error"},
+	}}
+	p := newFakePool(t, d, &GraphOption{
+		PoolOption:  PoolOption{Address: "127.0.0.1:9669", Space: "sf1"},
+		RetryOption: RetryOption{RetryTimes: 3},
+	})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	p.OutputCh = make(chan []string, 10)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+
+	// the retryable result is retried.
+	r, err := s.Execute("RETURN 1")
+	assert.NoError(t, err)
+	assert.True(t, r.IsSucceed())
+	assert.Equal(t, int32(2), r.GetRowSize())
+	assert.Equal(t, ResultHash([][]string{{"1", "a"}, {"2", "b"}}), r.GetResultHash())
+	assert.Equal(t, 2, d.executed)
+
+	// the other failed results are not.
+	r, err = s.Execute("RETURN 1")
+	assert.NoError(t, err)
+	assert.False(t, r.IsSucceed())
+	assert.Equal(t, "semantic error", r.GetErrorMsg())
+	assert.Equal(t, 3, d.executed)
+
+	// the invalid parameters are never retried, and are returned as the failed response.
+	r, err = s.ExecuteWithParameter("RETURN $invalid", map[string]any{"invalid": nil})
+	assert.NoError(t, err)
+	assert.False(t, r.IsSucceed())
+	assert.Contains(t, r.GetErrorMsg(), ErrInvalidParams.Error())
+
+	// the broken connections are retried retry_times times.
+	d.results = nil
+	r, err = s.Execute("RETURN 1")
+	assert.NoError(t, err)
+	assert.False(t, r.IsSucceed())
+	assert.Equal(t, "connection refused", r.GetErrorMsg())
+	assert.Equal(t, 7, d.executed)
+
+	assert.Len(t, p.OutputCh, 4)
+	o := <-p.OutputCh
+	assert.Equal(t, len(OutputHeader), len(o))
+	assert.Equal(t, []string{"10", "true", "2", "1|a"}, []string{o[2], o[4], o[5], o[6]})
+	// the retried request records the error and the time of every attempt.
+	assert.Equal(t, []string{"2", "execution error"}, []string{o[13], o[14]})
+	assert.Len(t, strings.Split(o[15], "|"), 2)
+	<-p.OutputCh
+	<-p.OutputCh
+	o = <-p.OutputCh
+	assert.Equal(t, []string{"4", "connection refused", "false"}, []string{o[13], o[14], o[16]})
+
+	// the timeout fails the request at once without any retry.
+	d.delay = time.Second
+	_, err = s.Execute("RETURN 1", &ExecuteOption{Timeout: "1s1"})
+	assert.Error(t, err)
+	begin := time.Now()
+	r, err = s.Execute("RETURN 1", &ExecuteOption{Timeout: "10ms"})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(begin), d.delay)
+	assert.False(t, r.IsSucceed())
+	assert.Contains(t, r.GetErrorMsg(), ErrTimeout.Error())
+	assert.Equal(t, 8, d.executed)
+	o = <-p.OutputCh
+	assert.Equal(t, []string{"1", "true"}, []string{o[13], o[16]})
+	assert.NoError(t, p.Close())
+}
+
+func TestClientGolden(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden.csv")
+	assert.NoError(t, os.WriteFile(golden, []byte("key,rows,hash\n1,5,\n"), 0o644))
+	d := &fakeDriver{results: []Result{&fakeResult{table: [][]string{{"1"}}}}}
+	p := newFakePool(t, d, &GraphOption{
+		PoolOption:   PoolOption{Address: "127.0.0.1:9669", Space: "sf1"},
+		CsvOption:    CsvOption{CsvPath: writeCsv(t), CsvDelimiter: ",", CsvWithHeader: true},
+		ExpectOption: ExpectOption{GoldenPath: golden},
+	})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	p.OutputCh = make(chan []string, 10)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+
+	// only the request right after getData is checked against the golden result of the data.
+	_, err = s.GetData()
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = s.Execute("RETURN 1")
+		assert.NoError(t, err)
+	}
+	o := <-p.OutputCh
+	assert.Equal(t, "1", o[10])
+	assert.Contains(t, o[11], CheckFailed)
+	o = <-p.OutputCh
+	assert.Equal(t, []string{"", ""}, []string{o[10], o[11]})
+	assert.NoError(t, p.Close())
+}
+
+func TestClientExecuteChain(t *testing.T) {
+	d := &fakeDriver{results: []Result{&fakeResult{table: [][]string{{"1"}}}}}
+	p := newFakePool(t, d, &GraphOption{PoolOption: PoolOption{Address: "127.0.0.1:9669", Space: "sf1"}})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	p.OutputCh = make(chan []string, 10)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+	c := s.(*Client)
+
+	// the step which could not be rendered fails the chain, and the executed steps are still reported.
+	r, err := c.ExecuteChain([]*ChainStep{
+		{Name: "first", Stmt: "RETURN 1 AS id"},
+		{Name: "second", Stmt: "GO FROM {missing} OVER KNOWS"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, r.IsSucceed())
+	assert.Contains(t, r.GetErrorMsg(), "failed to render step second")
+	steps := r.(*ChainResponse).GetSteps()
+	assert.Len(t, steps, 2)
+	assert.True(t, steps[0].IsSucceed)
+	assert.False(t, steps[1].IsSucceed)
+	assert.Equal(t, 1, d.executed)
+	assert.Len(t, p.OutputCh, 1)
+	o := <-p.OutputCh
+	assert.Equal(t, []string{"false", "0"}, []string{o[4], o[5]})
+	assert.Contains(t, o[7], "failed to render step second")
+	// the response time of the output is the total of the steps as well as the one of the response.
+	assert.Equal(t, fmt.Sprint(r.GetResponseTime()), o[3])
+	assert.Equal(t, fmt.Sprint(r.GetLatency()), o[2])
+	assert.NoError(t, p.Close())
+}
+
+func TestClientOpenConn(t *testing.T) {
+	d := &fakeDriver{results: []Result{&fakeResult{}}}
+	p := newFakePool(t, d, &GraphOption{PoolOption: PoolOption{Address: "127.0.0.1:9669", Space: "sf1"}})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+	c := s.(*Client)
+
+	// the connection opened by the session replaces the one of the driver, and is used by the async requests.
+	opened := 0
+	assert.NoError(t, c.OpenConn(func() (Conn, error) {
+		opened++
+		return &fakeConn{driver: d, host: "192.168.8.6:9669"}, nil
+	}))
+	assert.Equal(t, 1, d.closed)
+	assert.Equal(t, "192.168.8.6:9669", connHost(c.conn))
+	conn, err := c.acquireConn()
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.8.6:9669", connHost(conn))
+	assert.Equal(t, 2, opened)
+	assert.Equal(t, 1, d.conns)
+
+	assert.Error(t, c.OpenConn(func() (Conn, error) { return nil, fmt.Errorf("connection refused") }))
+	assert.NoError(t, p.Close())
+	assert.ErrorIs(t, c.OpenConn(func() (Conn, error) { return &fakeConn{driver: d}, nil }), ErrSessionClosed)
+}
+
+func TestClientBreaker(t *testing.T) {
+	d := &fakeDriver{}
+	p := newFakePool(t, d, &GraphOption{
+		PoolOption:    PoolOption{Address: "127.0.0.1:9669", Space: "sf1"},
+		RetryOption:   RetryOption{RetryTimes: 3},
+		BreakerOption: BreakerOption{BreakerFailures: 2},
+	})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	p.OutputCh = make(chan []string, 10)
+	s, err := p.GetSession()
+	assert.NoError(t, err)
+
+	// the retries stop once the breaker opens.
+	r, err := s.Execute("RETURN 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "connection refused", r.GetErrorMsg())
+	assert.Equal(t, 2, d.executed)
+	assert.Equal(t, BreakerOpen, p.getBreaker("").State())
+
+	// the rejected requests are neither sent nor written.
+	r, err = s.Execute("RETURN 1")
+	assert.NoError(t, err)
+	assert.False(t, r.IsSucceed())
+	assert.Contains(t, r.GetErrorMsg(), ErrBreakerOpen.Error())
+	assert.Equal(t, 2, d.executed)
+	assert.Len(t, p.OutputCh, 1)
+
+	// the statement timeouts open the breaker, unless they are ignored.
+	p.breakers = map[string]*Breaker{}
+	d.results = []Result{&fakeResult{}}
+	d.delay = time.Second
+	for i := 0; i < 2; i++ {
+		r, err = s.Execute("RETURN 1", &ExecuteOption{Timeout: "1ms"})
+		assert.NoError(t, err)
+		assert.Contains(t, r.GetErrorMsg(), ErrTimeout.Error())
+	}
+	assert.Equal(t, BreakerOpen, p.getBreaker("").State())
+	p.breakers = map[string]*Breaker{}
+	p.option.BreakerIgnoreTimeouts = true
+	for i := 0; i < 3; i++ {
+		r, err = s.Execute("RETURN 1", &ExecuteOption{Timeout: "1ms"})
+		assert.NoError(t, err)
+		assert.Contains(t, r.GetErrorMsg(), ErrTimeout.Error())
+	}
+	assert.Equal(t, BreakerClosed, p.getBreaker("").State())
+	assert.NoError(t, p.Close())
+
+	// the breaker is per host if the connection knows its host.
+	d = &fakeDriver{
+		results: []Result{&fakeResult{}},
+		hosts:   []string{"192.168.8.6:9669", "192.168.8.7:9669"},
+		down:    map[string]bool{"192.168.8.6:9669": true},
+	}
+	p = newFakePool(t, d, &GraphOption{
+		PoolOption:    PoolOption{Address: "192.168.8.6:9669,192.168.8.7:9669", Space: "sf1"},
+		BreakerOption: BreakerOption{BreakerFailures: 2},
+	})
+	_, err = p.Init()
+	assert.NoError(t, err)
+	s1, err := p.GetSession()
+	assert.NoError(t, err)
+	s2, err := p.GetSession()
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		r, err = s1.Execute("RETURN 1")
+		assert.NoError(t, err)
+		assert.False(t, r.IsSucceed())
+		r, err = s2.Execute("RETURN 1")
+		assert.NoError(t, err)
+		assert.True(t, r.IsSucceed())
+	}
+	assert.Equal(t, BreakerOpen, p.getBreaker("192.168.8.6:9669").State())
+	assert.Equal(t, BreakerClosed, p.getBreaker("192.168.8.7:9669").State())
+	assert.NoError(t, p.Close())
+}
+
+func TestClientGetRows(t *testing.T) {
+	p := newFakePool(t, &fakeDriver{}, &GraphOption{PoolOption: PoolOption{Address: "127.0.0.1:9669", Space: "sf1"}})
+	_, err := p.Init()
+	assert.NoError(t, err)
+	ch := make(chan Data, 3)
+	p.sources = map[string]*DataSource{DefaultDataSource: {dataChs: []chan Data{ch}, timeout: 10 * time.Millisecond}}
+	gs, err := p.GetSession()
+	assert.NoError(t, err)
+	s := gs.(*Client)
+
+	_, err = s.GetRows(DefaultDataSource, 0)
+	assert.Error(t, err)
+	_, err = s.GetRows(DefaultDataSource, 2)
+	assert.ErrorIs(t, err, ErrDataEmpty)
+
+	// the batch is cut short once the data are not ready in time, or exhausted.
+	ch <- Data{"1"}
+	ch <- Data{"2"}
+	ch <- Data{"3"}
+	rows, err := s.GetRows(DefaultDataSource, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Data{{"1"}, {"2"}}, rows)
+	rows, err = s.GetRows(DefaultDataSource, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Data{{"3"}}, rows)
+	ch <- Data{"4"}
+	close(ch)
+	rows, err = s.GetRows(DefaultDataSource, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Data{{"4"}}, rows)
+	_, err = s.GetRows(DefaultDataSource, 2)
+	assert.ErrorIs(t, err, ErrDataExhausted)
+	assert.NoError(t, p.Close())
+}
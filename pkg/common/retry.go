@@ -0,0 +1,111 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	// RetryNetwork the class of the requests which fail without any result, e.g. the connection is broken.
+	RetryNetwork = "network"
+	// RetryServer the class of all the statements which fail in the server.
+	RetryServer = "server"
+)
+
+// attempt the outcome of one attempt of a request.
+type attempt struct {
+	// responseTime the client side response time in us.
+	responseTime int32
+	errorMsg     string
+}
+
+// RetryPolicy decides whether a failed request is retried, and how long to wait before the retry.
+type RetryPolicy struct {
+	option  RetryOption
+	retryOn []string
+}
+
+// NewRetryPolicy returns the policy of opt, the errors in defaults are retried if retry_on is empty.
+func NewRetryPolicy(opt RetryOption, defaults []string) *RetryPolicy {
+	retryOn := opt.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaults
+	}
+	return &RetryPolicy{option: opt, retryOn: retryOn}
+}
+
+// Retryable returns whether the request should be retried, err means there is no result at all.
+// The request is retried if retry_on has its class, i.e. network or server, or its error code,
+// the code ending with * matches the codes with the prefix, e.g. 42* matches the GQLSTATUS 42001.
+// The invalid parameters and the timeouts are never retried.
+func (p *RetryPolicy) Retryable(r Result, err error) bool {
+	if errors.Is(err, ErrInvalidParams) || errors.Is(err, ErrTimeout) {
+		return false
+	}
+	if err != nil {
+		return p.match(RetryNetwork, "")
+	}
+	if r == nil || r.IsSucceed() {
+		return false
+	}
+	return p.match(RetryServer, r.GetErrorCode())
+}
+
+func (p *RetryPolicy) match(class, code string) bool {
+	for _, e := range p.retryOn {
+		switch {
+		case e == class:
+			return true
+		case code == "":
+		case e == code:
+			return true
+		case strings.HasSuffix(e, "*") && strings.HasPrefix(code, strings.TrimSuffix(e, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// Interval returns how long to wait before the nth retry, which starts from 1.
+// The interval is retry_interval_us * retry_backoff^(n-1) at most retry_max_interval_us,
+// and is reduced randomly by retry_jitter of it.
+func (p *RetryPolicy) Interval(n int) time.Duration {
+	opt := p.option
+	d := float64(opt.RetryIntervalUs)
+	if opt.RetryBackoff > 1 && n > 1 {
+		d *= math.Pow(opt.RetryBackoff, float64(n-1))
+	}
+	if opt.RetryMaxIntervalUs > 0 && d > float64(opt.RetryMaxIntervalUs) {
+		d = float64(opt.RetryMaxIntervalUs)
+	}
+	if opt.RetryJitter > 0 {
+		d *= 1 - opt.RetryJitter*rand.Float64()
+	}
+	return time.Duration(d) * time.Microsecond
+}
+
+// Timeout returns the max total time of a request with the retries, 0 means no limit.
+func (p *RetryPolicy) Timeout() time.Duration {
+	return time.Duration(p.option.RetryTimeoutUs) * time.Microsecond
+}
+
+// Times returns the max retry times.
+func (p *RetryPolicy) Times() int {
+	return p.option.RetryTimes
+}
+
+// sleep waits for d, it returns false if ctx is done before that.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryable(t *testing.T) {
+	failed := &fakeResult{errorCode: "42001", errorMsg: "syntax error"}
+	succeed := &fakeResult{}
+
+	p := NewRetryPolicy(RetryOption{}, []string{RetryNetwork})
+	assert.True(t, p.Retryable(nil, fmt.Errorf("connection refused")))
+	assert.False(t, p.Retryable(nil, fmt.Errorf("%w: invalid", ErrInvalidParams)))
+	assert.False(t, p.Retryable(failed, nil))
+	assert.False(t, p.Retryable(succeed, nil))
+
+	// retry_on overrides the defaults of the driver.
+	p = NewRetryPolicy(RetryOption{RetryOn: []string{RetryServer}}, []string{RetryNetwork})
+	assert.False(t, p.Retryable(nil, fmt.Errorf("connection refused")))
+	assert.True(t, p.Retryable(failed, nil))
+	assert.False(t, p.Retryable(succeed, nil))
+
+	p = NewRetryPolicy(RetryOption{RetryOn: []string{"42001"}}, nil)
+	assert.True(t, p.Retryable(failed, nil))
+	p = NewRetryPolicy(RetryOption{RetryOn: []string{"42*"}}, nil)
+	assert.True(t, p.Retryable(failed, nil))
+	p = NewRetryPolicy(RetryOption{RetryOn: []string{"40*", "E_EXECUTION_ERROR"}}, nil)
+	assert.False(t, p.Retryable(failed, nil))
+}
+
+func TestRetryInterval(t *testing.T) {
+	p := NewRetryPolicy(RetryOption{RetryIntervalUs: 1000, RetryBackoff: 1}, nil)
+	assert.Equal(t, time.Millisecond, p.Interval(1))
+	assert.Equal(t, time.Millisecond, p.Interval(3))
+
+	p = NewRetryPolicy(RetryOption{RetryIntervalUs: 1000, RetryBackoff: 2, RetryMaxIntervalUs: 5000}, nil)
+	assert.Equal(t, time.Millisecond, p.Interval(1))
+	assert.Equal(t, 2*time.Millisecond, p.Interval(2))
+	assert.Equal(t, 4*time.Millisecond, p.Interval(3))
+	assert.Equal(t, 5*time.Millisecond, p.Interval(4))
+
+	p = NewRetryPolicy(RetryOption{RetryIntervalUs: 1000, RetryBackoff: 1, RetryJitter: 0.5}, nil)
+	for i := 0; i < 100; i++ {
+		d := p.Interval(1)
+		assert.True(t, d >= 500*time.Microsecond && d <= time.Millisecond, d)
+	}
+}
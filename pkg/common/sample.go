@@ -0,0 +1,55 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SampleOption the options of sampling the rows in zipfian, hotspot and latest mode.
+type SampleOption struct {
+	// Skew the skew of zipfian and latest, which should be greater than 1.
+	Skew float64
+	// HotspotKeys the ratio of the hot rows in hotspot mode.
+	HotspotKeys float64
+	// HotspotTraffic the ratio of the traffic on the hot rows in hotspot mode.
+	HotspotTraffic float64
+}
+
+// newSampler returns the function which picks the index of the rows in [0, n) by the mode.
+//
+//   - zipfian: the rows in the front are more frequent.
+//   - latest: the rows in the end are more frequent, i.e. the recently appended rows.
+//   - hotspot: HotspotTraffic of the picks are on the first HotspotKeys of the rows, the others are uniform.
+func newSampler(mode FeedMode, n int, r *rand.Rand, opt SampleOption) (func() int, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("no data to sample")
+	}
+	switch mode {
+	case FeedZipfian, FeedLatest:
+		if opt.Skew <= 1 {
+			return nil, fmt.Errorf("skew should be greater than 1")
+		}
+		zipf := rand.NewZipf(r, opt.Skew, 1, uint64(n-1))
+		if mode == FeedLatest {
+			return func() int { return n - 1 - int(zipf.Uint64()) }, nil
+		}
+		return func() int { return int(zipf.Uint64()) }, nil
+	case FeedHotspot:
+		if opt.HotspotKeys <= 0 || opt.HotspotKeys >= 1 || opt.HotspotTraffic <= 0 || opt.HotspotTraffic >= 1 {
+			return nil, fmt.Errorf("hotspot keys and traffic should be in (0, 1)")
+		}
+		hot := int(math.Ceil(float64(n) * opt.HotspotKeys))
+		if hot >= n {
+			return func() int { return r.Intn(n) }, nil
+		}
+		return func() int {
+			if r.Float64() < opt.HotspotTraffic {
+				return r.Intn(hot)
+			}
+			return hot + r.Intn(n-hot)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid sample mode: %s", mode)
+	}
+}
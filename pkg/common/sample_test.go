@@ -0,0 +1,45 @@
+package common
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	opt := SampleOption{Skew: 1.5, HotspotKeys: 0.1, HotspotTraffic: 0.9}
+	count := func(mode FeedMode) []int {
+		next, err := newSampler(mode, 100, rand.New(rand.NewSource(1)), opt)
+		assert.NoError(t, err)
+		counts := make([]int, 100)
+		for i := 0; i < 10000; i++ {
+			counts[next()]++
+		}
+		return counts
+	}
+	sum := func(counts []int) int {
+		s := 0
+		for _, c := range counts {
+			s += c
+		}
+		return s
+	}
+
+	zipfian := count(FeedZipfian)
+	assert.Greater(t, zipfian[0], zipfian[50])
+	latest := count(FeedLatest)
+	assert.Greater(t, latest[99], latest[50])
+	hotspot := count(FeedHotspot)
+	hot := sum(hotspot[:10])
+	assert.InDelta(t, 9000, hot, 300)
+
+	next, err := newSampler(FeedZipfian, 1, rand.New(rand.NewSource(1)), opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, next())
+
+	_, err = newSampler(FeedZipfian, 100, rand.New(rand.NewSource(1)), SampleOption{Skew: 1})
+	assert.Error(t, err)
+	_, err = newSampler(FeedHotspot, 100, rand.New(rand.NewSource(1)), SampleOption{HotspotKeys: 1, HotspotTraffic: 0.5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultDataSource the name of the data source configured by csv_path.
+const DefaultDataSource = ""
+
+// DataSource the data of a csv file, which are read by the vus through the channels.
+type DataSource struct {
+	Name    string
+	dataChs []chan Data
+	timeout time.Duration
+	// header the column names, nil if the csv file has no header.
+	header      []string
+	columnTypes map[string]string
+	// err returns the error which stops feeding the data, if the reader could fail after Feed, e.g. in streaming.
+	err func() error
+}
+
+// NewDataSource starts to feed the data of the csv file or the generator until ctx is done,
+// the errors after starting, e.g. a corrupt row in streaming, are logged by l if not nil.
+func NewDataSource(ctx context.Context, name string, opt *DataSourceOption, l Logger) (*DataSource, error) {
+	var reader interface {
+		ICsvReader
+		Header() []string
+	}
+	if len(opt.Generator) > 0 {
+		reader = NewGenerator(opt.Generator, opt.CsvDataLimit, FeedMode(opt.CsvFeedMode), opt.CsvSeed)
+	} else {
+		csvReader := NewCsvReader(
+			opt.CsvPath,
+			opt.CsvDelimiter,
+			opt.CsvWithHeader,
+			opt.CsvDataLimit,
+			FeedMode(opt.CsvFeedMode),
+			opt.CsvSeed,
+		)
+		csvReader.Streaming = opt.CsvStreaming
+		csvReader.Logger = l
+		csvReader.Sample = SampleOption{
+			Skew:           opt.CsvSkew,
+			HotspotKeys:    opt.CsvHotspotKeys,
+			HotspotTraffic: opt.CsvHotspotTraffic,
+		}
+		reader = csvReader
+	}
+	partitions := 1
+	if FeedMode(opt.CsvFeedMode) == FeedPartition {
+		partitions = opt.CsvPartitions
+	}
+	ds := &DataSource{
+		Name:    name,
+		dataChs: make([]chan Data, partitions),
+		timeout: time.Duration(opt.CsvGetTimeoutUs) * time.Microsecond,
+	}
+	dataChs := make([]chan<- Data, partitions)
+	for i := range ds.dataChs {
+		ds.dataChs[i] = make(chan Data, opt.CsvChannelSize)
+		dataChs[i] = ds.dataChs[i]
+	}
+	if err := reader.Feed(ctx, dataChs); err != nil {
+		return nil, err
+	}
+	if r, ok := reader.(interface{ Err() error }); ok {
+		ds.err = r.Err
+	}
+	ds.header = reader.Header()
+	ds.columnTypes = opt.CsvColumnTypes
+	return ds, nil
+}
+
+// Chan returns the channel of the vu, every vu reads its own channel in partition mode.
+// vuID starts from 1, 0 means there is no vu, e.g. in the init context.
+func (ds *DataSource) Chan(vuID uint64) chan Data {
+	if vuID == 0 {
+		return ds.dataChs[0]
+	}
+	return ds.dataChs[(vuID-1)%uint64(len(ds.dataChs))]
+}
+
+// Get gets the data of the vu, waits until the data are available, the timeout expires or ctx is done.
+// It returns ErrDataEmpty if there are no data in time, ErrDataExhausted if all the data have been read,
+// or the error which stops feeding the data.
+func (ds *DataSource) Get(ctx context.Context, vuID uint64) (Data, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(ds.timeout)
+	defer timer.Stop()
+	select {
+	case d, ok := <-ds.Chan(vuID):
+		if !ok {
+			return nil, ds.exhausted()
+		}
+		return d, nil
+	case <-timer.C:
+		return nil, ErrDataEmpty
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TryGet gets the data of the vu without blocking,
+// returns ErrDataEmpty if the data are not ready, ErrDataExhausted if all the data have been read.
+func (ds *DataSource) TryGet(vuID uint64) (Data, error) {
+	select {
+	case d, ok := <-ds.Chan(vuID):
+		if !ok {
+			return nil, ds.exhausted()
+		}
+		return d, nil
+	default:
+		return nil, ErrDataEmpty
+	}
+}
+
+// exhausted returns the error which stops feeding the data, or ErrDataExhausted if there is none.
+func (ds *DataSource) exhausted() error {
+	if ds.err != nil {
+		if err := ds.err(); err != nil {
+			return err
+		}
+	}
+	return ErrDataExhausted
+}
+
+// Value returns the data as they are used in js, i.e. the data itself if there is no header,
+// otherwise an object keyed by both the column index and the column name.
+// The values keyed by the index are always strings as before,
+// and the values keyed by the name are converted to the types in csv_column_types.
+func (ds *DataSource) Value(d Data) (any, error) {
+	if ds.header == nil {
+		return d, nil
+	}
+	v := make(map[string]any, len(d)+len(ds.header))
+	for i, s := range d {
+		v[strconv.Itoa(i)] = s
+	}
+	for i, column := range ds.header {
+		if i >= len(d) {
+			break
+		}
+		cv, err := convertColumn(d[i], ColumnType(ds.columnTypes[column]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of column %s: %w", column, err)
+		}
+		v[column] = cv
+	}
+	return v, nil
+}
+
+func convertColumn(s string, t ColumnType) (any, error) {
+	switch t {
+	case ColumnInt:
+		return strconv.ParseInt(s, 10, 64)
+	case ColumnFloat:
+		return strconv.ParseFloat(s, 64)
+	case ColumnBool:
+		return strconv.ParseBool(s)
+	default:
+		return s, nil
+	}
+}
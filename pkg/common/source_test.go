@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := &DataSourceOption{CsvOption: CsvOption{CsvPath: writeCsv(t), CsvWithHeader: true, CsvFeedMode: string(FeedOnce)}}
+	makeDefaultCsvOption(&opt.CsvOption)
+	ds, err := NewDataSource(ctx, "person", opt, nil)
+	assert.NoError(t, err)
+	var got []string
+	for {
+		d, err := ds.Get(ctx, 1)
+		if err == ErrDataExhausted {
+			break
+		}
+		assert.NoError(t, err)
+		got = append(got, d[0])
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, got)
+}
+
+func TestValidateDataSources(t *testing.T) {
+	opt := MakeDefaultOption(&GraphOption{
+		PoolOption:  PoolOption{Address: "127.0.0.1:9669", Space: "test"},
+		DataSources: map[string]*DataSourceOption{"person": {}},
+	})
+	assert.Error(t, ValidateOption(opt))
+	opt.DataSources["person"].CsvPath = "person.csv"
+	assert.NoError(t, ValidateOption(opt))
+	opt.DataSources["person"].CsvFeedMode = "unknown"
+	assert.Error(t, ValidateOption(opt))
+}
+
+func TestDataSourceGet(t *testing.T) {
+	ch := make(chan Data, 1)
+	ds := &DataSource{dataChs: []chan Data{ch}, timeout: 10 * time.Millisecond}
+	_, err := ds.TryGet(1)
+	assert.Equal(t, ErrDataEmpty, err)
+	_, err = ds.Get(context.Background(), 1)
+	assert.Equal(t, ErrDataEmpty, err)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		ch <- Data{"1"}
+	}()
+	d, err := ds.Get(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, Data{"1"}, d)
+
+	close(ch)
+	_, err = ds.Get(context.Background(), 1)
+	assert.Equal(t, ErrDataExhausted, err)
+	_, err = ds.TryGet(1)
+	assert.Equal(t, ErrDataExhausted, err)
+}
+
+func TestDataSourceValue(t *testing.T) {
+	ds := &DataSource{}
+	v, err := ds.Value(Data{"1", "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, Data{"1", "a"}, v)
+
+	ds = &DataSource{
+		header:      []string{"id", "name", "score", "active"},
+		columnTypes: map[string]string{"id": "int", "score": "float", "active": "bool"},
+	}
+	v, err = ds.Value(Data{"1", "a", "0.5", "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"0": "1", "1": "a", "2": "0.5", "3": "true",
+		"id": int64(1), "name": "a", "score": 0.5, "active": true,
+	}, v)
+	_, err = ds.Value(Data{"x", "a", "0.5", "true"})
+	assert.Error(t, err)
+}
@@ -1,7 +1,12 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
 )
 
 type (
@@ -11,6 +16,12 @@ type (
 	// pool policy
 	PoolPolicy string
 
+	// FeedMode how to feed the csv data
+	FeedMode string
+
+	// ColumnType the type the csv column is converted to
+	ColumnType string
+
 	// IClient common client
 	IClient interface {
 		Open() error
@@ -25,8 +36,30 @@ type (
 	// IGraphClient graph client
 	IGraphClient interface {
 		IClient
-		GetData() (Data, error)
-		Execute(stmt string) (IGraphResponse, error)
+		GetData(name ...string) (any, error)
+		TryGetData(name ...string) (any, error)
+		Execute(stmt string, opts ...*ExecuteOption) (IGraphResponse, error)
+		ExecuteWithParameter(stmt string, params map[string]any, opts ...*ExecuteOption) (IGraphResponse, error)
+		// RunWorkload picks a template in the workload and executes it.
+		RunWorkload() (IGraphResponse, error)
+		// ExecuteAsync executes the statement in the background, and returns a promise of the response.
+		ExecuteAsync(stmt string, opts ...*ExecuteOption) (*goja.Promise, error)
+		// ExecuteWithParameterAsync is the async version of ExecuteWithParameter.
+		ExecuteWithParameterAsync(stmt string, params map[string]any, opts ...*ExecuteOption) (*goja.Promise, error)
+		// ExecuteChain executes the steps in order as one request, the step could use the result of the previous one.
+		ExecuteChain(steps []*ChainStep, opts ...*ExecuteOption) (IGraphResponse, error)
+	}
+
+	// ExecuteOption the options of a statement.
+	ExecuteOption struct {
+		// Expect the expected result of the statement.
+		Expect *Expect `js:"expect"`
+		// Name the name of the statement, which is written to the output and tagged as name in the metrics.
+		Name string `js:"name"`
+		// Tags the extra k6 tags of the metrics.
+		Tags map[string]string `js:"tags"`
+		// Timeout the timeout of the statement including the retries, e.g. 500ms, empty means no timeout.
+		Timeout string `js:"timeout"`
 	}
 
 	// IGraphResponse graph response, just support some functions to user.
@@ -35,6 +68,16 @@ type (
 		GetLatency() int64
 		GetResponseTime() int32
 		GetRowSize() int32
+		GetErrorCode() string
+		GetErrorMsg() string
+		GetColumnNames() []string
+		// GetRows returns all the rows, the values are converted to js values.
+		GetRows() ([][]any, error)
+		GetRow(index int) ([]any, error)
+		// GetRecords returns all the rows as objects keyed by the column names.
+		GetRecords() ([]map[string]any, error)
+		// GetResultHash returns the hash of all the rows, see ResultHash.
+		GetResultHash() string
 	}
 
 	// IGraphClientPool graph client pool.
@@ -47,16 +90,22 @@ type (
 	}
 
 	ICsvReader interface {
-		ReadForever(dataCh chan<- Data) error
+		Feed(ctx context.Context, dataChs []chan<- Data) error
 	}
 
 	GraphOption struct {
-		PoolOption   `json:",inline"`
-		OutputOption `json:",inline"`
-		CsvOption    `json:",inline"`
-		RetryOption  `json:",inline"`
-		SSLOption    `json:",inline"`
-		ExtraOptions any `json:"extra_options,omitempty"`
+		PoolOption      `json:",inline"`
+		OutputOption    `json:",inline"`
+		CsvOption       `json:",inline"`
+		GeneratorOption `json:",inline"`
+		RetryOption     `json:",inline"`
+		BreakerOption   `json:",inline"`
+		SSLOption       `json:",inline"`
+		ExpectOption    `json:",inline"`
+		WorkloadOption  `json:",inline"`
+		// DataSources the named csv files besides csv_path, see IGraphClient.GetData.
+		DataSources  map[string]*DataSourceOption `json:"data_sources,omitempty"`
+		ExtraOptions any                          `json:"extra_options,omitempty"`
 	}
 
 	PoolOption struct {
@@ -70,6 +119,8 @@ type (
 		Password   string `json:"password"`
 		Space      string `json:"space"`
 		UseHttp    bool   `json:"use_http"`
+		// MaxInFlight the max number of the async requests of a session, see IGraphClient.ExecuteAsync.
+		MaxInFlight int `json:"max_in_flight"`
 	}
 
 	OutputOption struct {
@@ -89,19 +140,128 @@ type (
 		CsvWithHeader  bool   `json:"csv_with_header"`
 		CsvChannelSize int    `json:"csv_channel_size"`
 		CsvDataLimit   int    `json:"csv_data_limit"`
+		CsvFeedMode    string `json:"csv_feed_mode"`
+		CsvPartitions  int    `json:"csv_partitions"`
+		CsvSeed        int64  `json:"csv_seed"`
+		CsvStreaming   bool   `json:"csv_streaming"`
+		// CsvGetTimeoutUs how long GetData waits for the data.
+		CsvGetTimeoutUs int `json:"csv_get_timeout_us"`
+		// CsvSkew the skew in zipfian and latest mode.
+		CsvSkew float64 `json:"csv_skew,omitempty"`
+		// CsvHotspotKeys the ratio of the hot data in hotspot mode.
+		CsvHotspotKeys float64 `json:"csv_hotspot_keys,omitempty"`
+		// CsvHotspotTraffic the ratio of the traffic on the hot data in hotspot mode.
+		CsvHotspotTraffic float64 `json:"csv_hotspot_traffic,omitempty"`
+		// CsvColumnTypes the types of the columns keyed by the column name, see ColumnType.
+		CsvColumnTypes map[string]string `json:"csv_column_types,omitempty"`
+	}
+	GeneratorOption struct {
+		// Generator the columns generated on the fly instead of reading csv_path.
+		Generator []*ColumnGenerator `json:"generator,omitempty"`
 	}
+
+	// DataSourceOption the options of a data source, which reads csv_path or generates the data.
+	DataSourceOption struct {
+		CsvOption       `json:",inline"`
+		GeneratorOption `json:",inline"`
+	}
+
+	WorkloadOption struct {
+		// WorkloadPath the json or yaml file of the workload, see Workload.
+		WorkloadPath string `json:"workload_path"`
+	}
+
+	ExpectOption struct {
+		// GoldenPath the csv file with the expected results, see Golden.
+		GoldenPath string `json:"golden_path"`
+	}
+
 	RetryOption struct {
 		RetryTimes      int `json:"retry_times"`
 		RetryIntervalUs int `json:"retry_interval_us"`
 		RetryTimeoutUs  int `json:"retry_timeout_us"`
+		// RetryBackoff the multiplier of the interval after every retry, 1 means the fixed interval.
+		RetryBackoff float64 `json:"retry_backoff"`
+		// RetryMaxIntervalUs the max interval between the retries, 0 means no limit.
+		RetryMaxIntervalUs int `json:"retry_max_interval_us"`
+		// RetryJitter the ratio of the interval which is reduced randomly, in [0, 1].
+		RetryJitter float64 `json:"retry_jitter"`
+		// RetryOn the classes and the codes of the errors which are retried, see RetryPolicy.Retryable.
+		RetryOn []string `json:"retry_on,omitempty"`
+	}
+
+	// BreakerOption the options of the circuit breakers, which are per host, see Breaker.
+	BreakerOption struct {
+		// BreakerFailures the consecutive failures without any result to open the circuit breaker, 0 means no breaker.
+		BreakerFailures int `json:"breaker_failures"`
+		// BreakerOpenUs how long the breaker is open before the next probe.
+		BreakerOpenUs int `json:"breaker_open_us"`
+		// BreakerIgnoreTimeouts the statement timeouts of the script are not counted as failures,
+		// they are counted by default, since a graphd which hangs only ever times out.
+		BreakerIgnoreTimeouts bool `json:"breaker_ignore_timeouts"`
 	}
 )
 
 const (
 	ConnectionPool PoolPolicy = "connection"
 	SessionPool    PoolPolicy = "session"
+	// DedicatedClient every session connects by its own client instead of a pool, only in nebulagraph5.
+	DedicatedClient PoolPolicy = "client"
+)
+
+const (
+	// FeedCycle feeds the data in order, and starts over at the end.
+	FeedCycle FeedMode = "cycle"
+	// FeedOnce feeds the data in order only once, then the data are exhausted.
+	FeedOnce FeedMode = "once"
+	// FeedShuffle feeds the data in random order, and shuffles again at the end.
+	FeedShuffle FeedMode = "shuffle"
+	// FeedPartition splits the data into csv_partitions parts, every vu reads its own part in cycle.
+	FeedPartition FeedMode = "partition"
+	// FeedZipfian samples the data in zipfian distribution, the data in the front are more frequent.
+	FeedZipfian FeedMode = "zipfian"
+	// FeedHotspot samples csv_hotspot_traffic of the data from the first csv_hotspot_keys of the data.
+	FeedHotspot FeedMode = "hotspot"
+	// FeedLatest samples the data in zipfian distribution, the data in the end are more frequent.
+	FeedLatest FeedMode = "latest"
+)
+
+const (
+	ColumnString ColumnType = "string"
+	ColumnInt    ColumnType = "int"
+	ColumnFloat  ColumnType = "float"
+	ColumnBool   ColumnType = "bool"
 )
 
+var (
+	// ErrDataExhausted all the data have been read, e.g. in once mode.
+	ErrDataExhausted = errors.New("data exhausted")
+	// ErrDataEmpty the data are not ready for now, but there would be more.
+	ErrDataEmpty = errors.New("no Data at all")
+)
+
+// GetExecuteOption returns the first option, or the default one if there is no option.
+func GetExecuteOption(opts []*ExecuteOption) *ExecuteOption {
+	for _, opt := range opts {
+		if opt != nil {
+			return opt
+		}
+	}
+	return &ExecuteOption{}
+}
+
+// timeout returns the timeout of the statement, 0 means no timeout.
+func (o *ExecuteOption) timeout() (time.Duration, error) {
+	if o.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(o.Timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid timeout: %s", o.Timeout)
+	}
+	return d, nil
+}
+
 func MakeDefaultOption(opt *GraphOption) *GraphOption {
 	if opt == nil {
 		return nil
@@ -112,6 +272,34 @@ func MakeDefaultOption(opt *GraphOption) *GraphOption {
 	if opt.OutputChannelSize == 0 {
 		opt.OutputChannelSize = 10000
 	}
+	makeDefaultCsvOption(&opt.CsvOption)
+	for _, source := range opt.DataSources {
+		if source != nil {
+			makeDefaultCsvOption(&source.CsvOption)
+		}
+	}
+	if opt.MaxSize == 0 {
+		opt.MaxSize = 400
+	}
+	if opt.MaxInFlight == 0 {
+		opt.MaxInFlight = 16
+	}
+	if opt.RetryBackoff == 0 {
+		opt.RetryBackoff = 1
+	}
+	if opt.BreakerFailures > 0 && opt.BreakerOpenUs == 0 {
+		opt.BreakerOpenUs = 1000000
+	}
+	if opt.Username == "" {
+		opt.Username = "root"
+	}
+	if opt.Password == "" {
+		opt.Password = "nebula"
+	}
+	return opt
+}
+
+func makeDefaultCsvOption(opt *CsvOption) {
 	if opt.CsvPath != "" && opt.CsvDelimiter == "" {
 		opt.CsvDelimiter = ","
 	}
@@ -121,16 +309,21 @@ func MakeDefaultOption(opt *GraphOption) *GraphOption {
 	if opt.CsvDataLimit == 0 {
 		opt.CsvDataLimit = 500000
 	}
-	if opt.MaxSize == 0 {
-		opt.MaxSize = 400
+	if opt.CsvFeedMode == "" {
+		opt.CsvFeedMode = string(FeedCycle)
 	}
-	if opt.Username == "" {
-		opt.Username = "root"
+	if opt.CsvGetTimeoutUs == 0 {
+		opt.CsvGetTimeoutUs = 1000000
 	}
-	if opt.Password == "" {
-		opt.Password = "nebula"
+	if opt.CsvSkew == 0 {
+		opt.CsvSkew = 1.1
+	}
+	if opt.CsvHotspotKeys == 0 {
+		opt.CsvHotspotKeys = 0.2
+	}
+	if opt.CsvHotspotTraffic == 0 {
+		opt.CsvHotspotTraffic = 0.8
 	}
-	return opt
 }
 
 func ValidateOption(option *GraphOption) error {
@@ -143,6 +336,35 @@ func ValidateOption(option *GraphOption) error {
 	if option.Address == "" {
 		return fmt.Errorf("address is empty")
 	}
+	if option.MaxInFlight < 0 {
+		return fmt.Errorf("max_in_flight should be greater than 0")
+	}
+	if option.RetryTimes < 0 || option.RetryIntervalUs < 0 || option.RetryTimeoutUs < 0 || option.RetryMaxIntervalUs < 0 {
+		return fmt.Errorf("retry_times, retry_interval_us, retry_timeout_us and retry_max_interval_us should not be negative")
+	}
+	if option.RetryBackoff < 1 {
+		return fmt.Errorf("retry_backoff should not be less than 1")
+	}
+	if option.RetryJitter < 0 || option.RetryJitter > 1 {
+		return fmt.Errorf("retry_jitter should be in [0, 1]")
+	}
+	if option.BreakerFailures < 0 || option.BreakerOpenUs < 0 {
+		return fmt.Errorf("breaker_failures and breaker_open_us should not be negative")
+	}
+	if err := validateDataSourceOption(&DataSourceOption{CsvOption: option.CsvOption, GeneratorOption: option.GeneratorOption}); err != nil {
+		return err
+	}
+	for name, source := range option.DataSources {
+		if name == DefaultDataSource {
+			return fmt.Errorf("the name of data source is empty")
+		}
+		if source == nil || (source.CsvPath == "" && len(source.Generator) == 0) {
+			return fmt.Errorf("csv_path or generator of data source %s is empty", name)
+		}
+		if err := validateDataSourceOption(source); err != nil {
+			return fmt.Errorf("data source %s: %w", name, err)
+		}
+	}
 	if option.SslCaPemPath != "" {
 		if option.SslClientPemPath == "" || option.SslClientKeyPath == "" {
 			return fmt.Errorf("ssl_client_pem_path or ssl_client_key_path is empty")
@@ -151,3 +373,52 @@ func ValidateOption(option *GraphOption) error {
 
 	return nil
 }
+
+func validateDataSourceOption(option *DataSourceOption) error {
+	generated := len(option.Generator) > 0
+	if generated && option.CsvPath != "" {
+		return fmt.Errorf("csv_path and generator could not be used together")
+	}
+	if generated {
+		switch FeedMode(option.CsvFeedMode) {
+		case FeedCycle, FeedOnce, FeedPartition:
+		default:
+			return fmt.Errorf("csv_feed_mode %s is not supported by generator", option.CsvFeedMode)
+		}
+	}
+	switch FeedMode(option.CsvFeedMode) {
+	case FeedShuffle, FeedZipfian, FeedHotspot, FeedLatest:
+		if option.CsvStreaming {
+			return fmt.Errorf("csv_feed_mode %s is not supported with csv_streaming", option.CsvFeedMode)
+		}
+	}
+	switch FeedMode(option.CsvFeedMode) {
+	case FeedCycle, FeedOnce, FeedShuffle:
+	case FeedZipfian, FeedLatest:
+		if option.CsvSkew <= 1 {
+			return fmt.Errorf("csv_skew should be greater than 1")
+		}
+	case FeedHotspot:
+		if option.CsvHotspotKeys <= 0 || option.CsvHotspotKeys >= 1 ||
+			option.CsvHotspotTraffic <= 0 || option.CsvHotspotTraffic >= 1 {
+			return fmt.Errorf("csv_hotspot_keys and csv_hotspot_traffic should be in (0, 1)")
+		}
+	case FeedPartition:
+		if option.CsvPartitions <= 0 {
+			return fmt.Errorf("csv_partitions should be greater than 0 in partition mode")
+		}
+	default:
+		return fmt.Errorf("invalid csv_feed_mode: %s", option.CsvFeedMode)
+	}
+	if len(option.CsvColumnTypes) > 0 && !option.CsvWithHeader && !generated {
+		return fmt.Errorf("csv_column_types needs csv_with_header")
+	}
+	for column, t := range option.CsvColumnTypes {
+		switch ColumnType(t) {
+		case ColumnString, ColumnInt, ColumnFloat, ColumnBool:
+		default:
+			return fmt.Errorf("invalid type of column %s: %s", column, t)
+		}
+	}
+	return nil
+}
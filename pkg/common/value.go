@@ -0,0 +1,71 @@
+package common
+
+// The functions below build the values in a result set as js objects,
+// so that the values from different versions of NebulaGraph look the same in js.
+
+// NewVertex returns a vertex, properties are keyed by the tag name.
+func NewVertex(vid any, tags []string, properties map[string]map[string]any) map[string]any {
+	return map[string]any{
+		"vid":        vid,
+		"tags":       tags,
+		"properties": properties,
+	}
+}
+
+// NewEdge returns an edge.
+func NewEdge(src, dst any, edgeType string, rank int64, properties map[string]any) map[string]any {
+	return map[string]any{
+		"src":        src,
+		"dst":        dst,
+		"type":       edgeType,
+		"rank":       rank,
+		"properties": properties,
+	}
+}
+
+// NewPath returns a path, which is made up of the nodes and the relationships between them.
+func NewPath(nodes []any, relationships []any) map[string]any {
+	return map[string]any{
+		"nodes":         nodes,
+		"relationships": relationships,
+	}
+}
+
+// NewDate returns a date.
+func NewDate(year, month, day int) map[string]any {
+	return map[string]any{
+		"year":  year,
+		"month": month,
+		"day":   day,
+	}
+}
+
+// NewTime returns a time.
+func NewTime(hour, minute, second, microsecond int) map[string]any {
+	return map[string]any{
+		"hour":        hour,
+		"minute":      minute,
+		"second":      second,
+		"microsecond": microsecond,
+	}
+}
+
+// NewDateTime returns a datetime.
+func NewDateTime(year, month, day, hour, minute, second, microsecond int) map[string]any {
+	dt := NewDate(year, month, day)
+	for k, v := range NewTime(hour, minute, second, microsecond) {
+		dt[k] = v
+	}
+	return dt
+}
+
+// NewRecord returns the row as an object keyed by the column names.
+func NewRecord(columns []string, row []any) map[string]any {
+	record := make(map[string]any, len(columns))
+	for i, c := range columns {
+		if i < len(row) {
+			record[c] = row[i]
+		}
+	}
+	return record
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderRegex matches the placeholders in the templates, e.g. {0} or {id}.
+var placeholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+type (
+	// Workload the weighted statement templates, one of which is picked to run every time, e.g.
+	//
+	//	templates:
+	//	  - name: go
+	//	    weight: 80
+	//	    stmt: GO 2 STEPS FROM {0} OVER KNOWS YIELD dst(edge)
+	//	  - name: insert
+	//	    weight: 20
+	//	    source: person
+	//	    stmt: INSERT VERTEX Person(firstName) VALUES $id:($name)
+	//	    params: {id: id, name: firstName}
+	Workload struct {
+		// Seed the seed to pick the templates, 0 means a random seed.
+		Seed      int64           `json:"seed" yaml:"seed"`
+		Templates []*StmtTemplate `json:"templates" yaml:"templates"`
+		total     int
+	}
+
+	// StmtTemplate the statement template in workload.
+	StmtTemplate struct {
+		Name   string `json:"name" yaml:"name"`
+		Weight int    `json:"weight" yaml:"weight"`
+		// Stmt the statement, the placeholders {column} are replaced by the data,
+		// the column is the index or the name of the column.
+		Stmt string `json:"stmt" yaml:"stmt"`
+		// Source the data source to read the data, the default one if empty.
+		Source string `json:"source" yaml:"source"`
+		// Params the parameters of the statement, keyed by the parameter name, the values are the columns.
+		Params map[string]string `json:"params" yaml:"params"`
+	}
+)
+
+// LoadWorkload loads the workload from the yaml file, whose extension is .yaml or .yml, or the json file.
+func LoadWorkload(path string) (*Workload, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &Workload{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(bs, w)
+	default:
+		err = json.Unmarshal(bs, w)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid workload %s: %w", path, err)
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Workload) validate() error {
+	if len(w.Templates) == 0 {
+		return fmt.Errorf("no templates in workload")
+	}
+	names := make(map[string]struct{}, len(w.Templates))
+	w.total = 0
+	for _, t := range w.Templates {
+		if t.Name == "" {
+			return fmt.Errorf("the name of template is empty")
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("duplicate template: %s", t.Name)
+		}
+		names[t.Name] = struct{}{}
+		if t.Stmt == "" {
+			return fmt.Errorf("the stmt of template %s is empty", t.Name)
+		}
+		if t.Weight <= 0 {
+			return fmt.Errorf("the weight of template %s should be greater than 0", t.Name)
+		}
+		w.total += t.Weight
+	}
+	return nil
+}
+
+// NewRand returns the random source to pick the templates for the vu.
+func (w *Workload) NewRand(vuID uint64) *rand.Rand {
+	if w.Seed == 0 {
+		return rand.New(rand.NewSource(rand.Int63()))
+	}
+	return rand.New(rand.NewSource(w.Seed + int64(vuID)))
+}
+
+// Pick picks a template by the weights.
+func (w *Workload) Pick(r *rand.Rand) *StmtTemplate {
+	n := r.Intn(w.total)
+	for _, t := range w.Templates {
+		if n < t.Weight {
+			return t
+		}
+		n -= t.Weight
+	}
+	return w.Templates[len(w.Templates)-1]
+}
+
+// NeedData returns whether the template reads the data.
+func (t *StmtTemplate) NeedData() bool {
+	return len(t.Params) > 0 || placeholderRegex.MatchString(t.Stmt)
+}
+
+// Render replaces the placeholders in the statement and builds the parameters by the data,
+// which is returned by DataSource.Value.
+func (t *StmtTemplate) Render(data any) (string, map[string]any, error) {
+	var err error
+	stmt := placeholderRegex.ReplaceAllStringFunc(t.Stmt, func(s string) string {
+		v, ok := lookupColumn(data, s[1:len(s)-1])
+		if !ok {
+			err = fmt.Errorf("unknown column %s in template %s", s, t.Name)
+			return s
+		}
+		return fmt.Sprint(v)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(t.Params) == 0 {
+		return stmt, nil, nil
+	}
+	params := make(map[string]any, len(t.Params))
+	for name, column := range t.Params {
+		v, ok := lookupColumn(data, column)
+		if !ok {
+			return "", nil, fmt.Errorf("unknown column %s in template %s", column, t.Name)
+		}
+		params[name] = v
+	}
+	return stmt, params, nil
+}
+
+func lookupColumn(data any, column string) (any, bool) {
+	switch d := data.(type) {
+	case Data:
+		i, err := strconv.Atoi(column)
+		if err != nil || i < 0 || i >= len(d) {
+			return nil, false
+		}
+		return d[i], true
+	case map[string]any:
+		v, ok := d[column]
+		return v, ok
+	default:
+		return nil, false
+	}
+}
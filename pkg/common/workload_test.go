@@ -0,0 +1,64 @@
+package common
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWorkload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workload.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+seed: 1
+templates:
+  - name: go
+    weight: 80
+    stmt: GO 2 STEPS FROM {0} OVER KNOWS YIELD dst(edge)
+  - name: insert
+    weight: 20
+    source: person
+    stmt: INSERT VERTEX Person(firstName) VALUES $id:($name)
+    params: {id: id, name: firstName}
+  - name: count
+    weight: 1
+    stmt: MATCH (v) RETURN count(v)
+`), 0o644))
+	w, err := LoadWorkload(path)
+	assert.NoError(t, err)
+	assert.Len(t, w.Templates, 3)
+	assert.True(t, w.Templates[0].NeedData())
+	assert.True(t, w.Templates[1].NeedData())
+	assert.False(t, w.Templates[2].NeedData())
+
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[w.Pick(r).Name]++
+	}
+	assert.InDelta(t, 8000*100/101, counts["go"], 300)
+	assert.InDelta(t, 2000*100/101, counts["insert"], 300)
+
+	path = filepath.Join(t.TempDir(), "workload.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"templates": [{"name": "go", "stmt": "GO FROM 1 OVER KNOWS"}]}`), 0o644))
+	_, err = LoadWorkload(path)
+	assert.Error(t, err)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tpl := &StmtTemplate{Name: "go", Stmt: "GO FROM {0} OVER KNOWS"}
+	stmt, params, err := tpl.Render(Data{"933"})
+	assert.NoError(t, err)
+	assert.Equal(t, "GO FROM 933 OVER KNOWS", stmt)
+	assert.Nil(t, params)
+	_, _, err = tpl.Render(Data{})
+	assert.Error(t, err)
+
+	tpl = &StmtTemplate{Name: "insert", Stmt: "INSERT VERTEX Person(firstName) VALUES {id}:($name)", Params: map[string]string{"name": "firstName"}}
+	stmt, params, err = tpl.Render(map[string]any{"0": "933", "id": int64(933), "firstName": "Tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT VERTEX Person(firstName) VALUES 933:($name)", stmt)
+	assert.Equal(t, map[string]any{"name": "Tom"}, params)
+}
@@ -1,16 +1,16 @@
 package nebulagraph
 
 import (
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vesoft-inc/k6-plugin/pkg/common"
 	graph "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
 )
 
 const EnvRetryTimes = "NEBULA_RETRY_TIMES"
@@ -19,438 +19,292 @@ const EnvRetryTimeoutUs = "NEBULA_RETRY_TIMEOUT_US"
 
 type (
 	// GraphPool nebula connection pool
-	GraphPool struct {
-		DataCh      chan common.Data
-		OutputCh    chan []string
-		initialized bool
-		closed      bool
-		mutex       sync.Mutex
-		csvReader   common.ICsvReader
-		connPool    *graph.ConnectionPool
-		sessPool    *graph.SessionPool
-		clients     []common.IGraphClient
-		graphOption *common.GraphOption
-		logger      logger
-	}
-
-	// GraphClient a wrapper for nebula client, could read data from DataCh
+	GraphPool = common.Pool
+
+	// driver connects to the NebulaGraph 3.x by the connection pools or the session pool of nebula-go.
+	// There is a connection pool per host, so that every session knows its host, see conn.Host.
+	driver struct {
+		option    *common.GraphOption
+		hosts     []common.HostAddress
+		connPools []*graph.ConnectionPool
+		// next the host of the next session, the sessions are spread over the hosts in turn.
+		next     uint32
+		sessPool *graph.SessionPool
+		// schemas the schemas of the tags and edge types used in batch insert.
+		schemas     map[string]*schema
+		schemaMutex sync.Mutex
+	}
+
+	// executor is implemented by both graph.Session and graph.SessionPool.
+	executor interface {
+		ExecuteWithParameter(stmt string, params map[string]any) (*graph.ResultSet, error)
+	}
+
+	// conn executes the statements on a session of the connection pool of a host, or on the session pool.
+	// The session is replaced once a statement on it times out, and is moved to the next host once it is broken.
+	conn struct {
+		driver   *driver
+		executor executor
+		release  func()
+		// index the index of the host of the session in the connection policy.
+		index int
+	}
+
+	// executeResult the result of a statement executed in the background.
+	executeResult struct {
+		rs  *graph.ResultSet
+		err error
+	}
+
+	// GraphClient a wrapper for nebula client, adds the batch insert to the session.
 	GraphClient struct {
-		Client *graph.Session
-		Pool   *GraphPool
-		DataCh chan common.Data
-		logger logger
+		*common.Client
+		driver *driver
 	}
 
 	// Response a wrapper for nebula resultSet
 	Response struct {
 		*graph.ResultSet
-		ResponseTime int32
+		common.ResultMeta
 	}
 
-	csvReaderStrategy int
-
-	output struct {
-		timeStamp    int64
-		nGQL         string
-		latency      int64
-		responseTime int32
-		isSucceed    bool
-		rows         int32
-		errorMsg     string
-		firstRecord  string
-	}
-
-	logger interface {
-		Info(msg string)
-		Warn(msg string)
-		Debug(msg string)
-		Error(msg string)
-		Fatal(msg string)
+	// nebulaLogger adapts the logger of the pool to the session pool of nebula-go.
+	nebulaLogger struct {
+		common.Logger
 	}
 )
 
+var _ common.Driver = &driver{}
 var _ common.IGraphClient = &GraphClient{}
-var _ common.IGraphClientPool = &GraphPool{}
-
-const (
-	// AllInOne read csv sequentially
-	AllInOne csvReaderStrategy = iota
-	// Separate read csv concurrently
-	Separate
-)
-
-func formatOutput(o *output) []string {
-	return []string{
-		strconv.FormatInt(o.timeStamp, 10),
-		o.nGQL,
-		strconv.Itoa(int(o.latency)),
-		strconv.Itoa(int(o.responseTime)),
-		strconv.FormatBool(o.isSucceed),
-		strconv.Itoa(int(o.rows)),
-		o.firstRecord,
-		o.errorMsg,
-	}
-}
-
-var outputHeader []string = []string{
-	"timestamp",
-	"nGQL",
-	"latency",
-	"responseTime",
-	"isSucceed",
-	"rows",
-	"firstRecord",
-	"errorMsg",
-}
+var _ common.Result = &Response{}
 
 // NewNebulaGraph New for k6 initialization.
 func NewNebulaGraph() *GraphPool {
-	return &GraphPool{}
+	return common.NewPool(&driver{})
 }
 
-// Init initializes nebula pool with address and concurrent, by default the bufferSize is 20000
-func (gp *GraphPool) Init() (common.IGraphClientPool, error) {
-	var (
-		err error
-	)
-	gp.mutex.Lock()
-	defer gp.mutex.Unlock()
-	if gp.initialized {
-		return gp, nil
-	}
-
-	if gp.closed {
-		return nil, fmt.Errorf("pool has been closed")
+// Open opens the connection pool or the session pool by pool_policy.
+func (d *driver) Open(opt *common.GraphOption, hosts []common.HostAddress, l common.Logger) error {
+	d.option = opt
+	d.hosts = hosts
+	addresses := make([]graph.HostAddress, 0, len(hosts))
+	for _, h := range hosts {
+		addresses = append(addresses, graph.HostAddress{Host: h.Host, Port: h.Port})
 	}
-	gp.logger.Debug("initializing graph pool")
-	switch gp.graphOption.PoolPolicy {
+	switch opt.PoolPolicy {
 	case string(common.ConnectionPool):
-		err = gp.initConnectionPool()
+		return d.initConnectionPool(addresses)
 	case string(common.SessionPool):
-		err = gp.initSessionPool()
+		return d.initSessionPool(addresses, l)
 	default:
-		return nil, fmt.Errorf("invalid pool policy: %s, need connection or session", gp.graphOption.PoolPolicy)
+		return fmt.Errorf("invalid pool policy: %s, need connection or session", opt.PoolPolicy)
 	}
-	if err != nil {
-		return nil, err
-	}
-	gp.initialized = true
-	if gp.graphOption.Output != "" {
-		channelBufferSize := gp.graphOption.OutputChannelSize
-		gp.OutputCh = make(chan []string, channelBufferSize)
-		writer := common.NewCsvWriter(gp.graphOption.Output, ",", outputHeader, gp.OutputCh)
-		if err := writer.WriteForever(); err != nil {
-			return nil, err
-		}
-	}
-	if gp.graphOption.CsvPath != "" {
-		gp.csvReader = common.NewCsvReader(
-			gp.graphOption.CsvPath,
-			gp.graphOption.CsvDelimiter,
-			gp.graphOption.CsvWithHeader,
-			gp.graphOption.CsvDataLimit,
-		)
-		gp.DataCh = make(chan common.Data, gp.graphOption.CsvChannelSize)
-		if err := gp.csvReader.ReadForever(gp.DataCh); err != nil {
-			return nil, err
-		}
+}
+
+func (d *driver) sslConfig() (*tls.Config, error) {
+	if d.option.SslCaPemPath == "" {
+		return nil, nil
 	}
-	return gp, nil
+	return graph.GetDefaultSSLConfig(
+		d.option.SslCaPemPath,
+		d.option.SslClientPemPath,
+		d.option.SslClientKeyPath)
 }
 
-func (gp *GraphPool) initConnectionPool() error {
-	addr := gp.graphOption.Address
-	hosts, err := gp.validate(addr)
+// initConnectionPool opens a connection pool per host, every one of which is up to max_size,
+// and min_size is spread over them.
+func (d *driver) initConnectionPool(hosts []graph.HostAddress) error {
+	conf := graph.GetDefaultConf()
+	conf.MaxConnPoolSize = d.option.MaxSize
+	conf.MinConnPoolSize = (d.option.MinSize + len(hosts) - 1) / len(hosts)
+	conf.TimeOut = time.Duration(d.option.TimeoutUs) * time.Microsecond
+	conf.IdleTime = time.Duration(d.option.IdleTimeUs) * time.Microsecond
+	if d.option.UseHttp {
+		conf.UseHTTP2 = true
+	}
+	sslConfig, err := d.sslConfig()
 	if err != nil {
 		return err
 	}
-	gp.clients = make([]common.IGraphClient, 0, gp.graphOption.MaxSize)
-	conf := graph.GetDefaultConf()
-	conf.MaxConnPoolSize = gp.graphOption.MaxSize
-	conf.MinConnPoolSize = gp.graphOption.MinSize
-	conf.TimeOut = time.Duration(gp.graphOption.TimeoutUs) * time.Microsecond
-	conf.IdleTime = time.Duration(gp.graphOption.IdleTimeUs) * time.Microsecond
-	var sslConfig *tls.Config
-	if gp.graphOption.SslCaPemPath != "" {
-		var err error
-		sslConfig, err = graph.GetDefaultSSLConfig(
-			gp.graphOption.SslCaPemPath,
-			gp.graphOption.SslClientPemPath,
-			gp.graphOption.SslClientKeyPath)
+	for _, h := range hosts {
+		pool, err := graph.NewSslConnectionPool([]graph.HostAddress{h}, conf, sslConfig, graph.DefaultLogger{})
 		if err != nil {
 			return err
 		}
+		d.connPools = append(d.connPools, pool)
 	}
-	if gp.graphOption.UseHttp {
-		conf.UseHTTP2 = true
-	}
-
-	pool, err := graph.NewSslConnectionPool(hosts, conf, sslConfig, graph.DefaultLogger{})
-	if err != nil {
-		return err
-	}
-	gp.connPool = pool
-
 	return nil
 }
 
-func (gp *GraphPool) initSessionPool() error {
-	addr := gp.graphOption.Address
-	hosts, err := gp.validate(addr)
+func (d *driver) initSessionPool(hosts []graph.HostAddress, l common.Logger) error {
+	sslConfig, err := d.sslConfig()
 	if err != nil {
 		return err
 	}
-	var sslConfig *tls.Config
-	if gp.graphOption.SslCaPemPath != "" {
-		var err error
-		sslConfig, err = graph.GetDefaultSSLConfig(
-			gp.graphOption.SslCaPemPath,
-			gp.graphOption.SslClientPemPath,
-			gp.graphOption.SslClientKeyPath)
-		if err != nil {
-			return err
-		}
-	}
 	conf, err := graph.NewSessionPoolConf(
-		gp.graphOption.Username,
-		gp.graphOption.Password,
+		d.option.Username,
+		d.option.Password,
 		hosts,
-		gp.graphOption.Space,
-		graph.WithTimeOut(time.Duration(gp.graphOption.TimeoutUs)*time.Microsecond),
-		graph.WithIdleTime(time.Duration(gp.graphOption.IdleTimeUs)*time.Microsecond),
-		graph.WithMaxSize(gp.graphOption.MaxSize),
-		graph.WithMinSize(gp.graphOption.MinSize),
+		d.option.Space,
+		graph.WithTimeOut(time.Duration(d.option.TimeoutUs)*time.Microsecond),
+		graph.WithIdleTime(time.Duration(d.option.IdleTimeUs)*time.Microsecond),
+		graph.WithMaxSize(d.option.MaxSize),
+		graph.WithMinSize(d.option.MinSize),
 		graph.WithSSLConfig(sslConfig),
-		graph.WithHTTP2(gp.graphOption.UseHttp),
+		graph.WithHTTP2(d.option.UseHttp),
 	)
 	if err != nil {
 		return err
 	}
-	pool, err := graph.NewSessionPool(*conf, gp.logger)
+	pool, err := graph.NewSessionPool(*conf, &nebulaLogger{Logger: l})
 	if err != nil {
 		return err
 	}
-	gp.sessPool = pool
-
+	d.sessPool = pool
 	return nil
 }
 
-func (gp *GraphPool) validate(address string) ([]graph.HostAddress, error) {
-	var hosts []graph.HostAddress
-	addrs := strings.Split(address, ",")
-	if len(addrs) == 0 {
-		return nil, fmt.Errorf("Invalid address: %s", address)
+// Conn returns a connection with a session, see conn.open.
+func (d *driver) Conn() (common.Conn, error) {
+	c := &conn{driver: d}
+	if len(d.connPools) > 0 {
+		c.index = int((atomic.AddUint32(&d.next, 1) - 1) % uint32(len(d.connPools)))
 	}
-	for _, addr := range addrs {
-		hostAndPort := strings.Split(addr, ":")
-		if len(hostAndPort) != 2 {
-			return nil, fmt.Errorf("Invalid address: %s", addr)
-		}
-		port, err := strconv.Atoi(hostAndPort[1])
-		if err != nil {
-			return nil, err
-		}
-		hosts = append(hosts, graph.HostAddress{
-			Host: hostAndPort[0],
-			Port: port,
-		})
+	if err := c.open(); err != nil {
+		return nil, err
 	}
-	return hosts, nil
+	return c, nil
 }
 
-// Deprecated ConfigCsvStrategy sets csv reader strategy
-func (gp *GraphPool) ConfigCsvStrategy(strategy int) {
-	return
+// RetryOn retries only the broken connections and the execution errors by default,
+// the other errors, e.g. E_SEMANTIC_ERROR, would return directly.
+func (d *driver) RetryOn() []string {
+	return []string{common.RetryNetwork, nebula.ErrorCode_E_EXECUTION_ERROR.String()}
 }
 
-// Close closes the nebula pool
-func (gp *GraphPool) Close() error {
-	gp.mutex.Lock()
-	defer gp.mutex.Unlock()
-	if !gp.initialized {
-		return nil
-	}
-	for _, s := range gp.clients {
-		if s != nil {
-			s.Close()
-		}
-	}
-	if gp.connPool != nil {
-		gp.connPool.Close()
+// Wrap adds the batch insert to the session.
+func (d *driver) Wrap(c *common.Client) common.IGraphClient {
+	return &GraphClient{Client: c, driver: d}
+}
+
+func (d *driver) Close() error {
+	for _, pool := range d.connPools {
+		pool.Close()
 	}
-	if gp.sessPool != nil {
-		gp.sessPool.Close()
+	d.connPools = nil
+	if d.sessPool != nil {
+		d.sessPool.Close()
+		d.sessPool = nil
 	}
-	gp.closed = true
-
 	return nil
 }
 
-// GetSession gets the session from pool
-func (gp *GraphPool) GetSession() (common.IGraphClient, error) {
-	if gp.connPool != nil {
-		gp.mutex.Lock()
-		defer gp.mutex.Unlock()
-		c, err := gp.connPool.GetSession(
-			gp.graphOption.Username,
-			gp.graphOption.Password,
-		)
-		if err != nil {
-			return nil, err
-		}
-		_, err = c.Execute(fmt.Sprintf("USE %s", gp.graphOption.Space))
-		if err != nil {
-			return nil, err
+// open gets a session from the connection pool of the host and uses the space, or the next host if it fails,
+// or uses the session pool itself, which could execute concurrently.
+func (c *conn) open() error {
+	d := c.driver
+	if d.sessPool != nil {
+		c.executor, c.release = d.sessPool, func() {}
+		return nil
+	}
+	var err error
+	for i := range d.connPools {
+		index := (c.index + i) % len(d.connPools)
+		var sess *graph.Session
+		if sess, err = d.openSession(d.connPools[index]); err == nil {
+			c.index, c.executor, c.release = index, sess, sess.Release
+			return nil
 		}
-		s := &GraphClient{Client: c, Pool: gp, DataCh: gp.DataCh, logger: gp.logger}
-		gp.clients = append(gp.clients, s)
-		return s, nil
-	} else {
-		s := &GraphClient{Client: nil, Pool: gp, DataCh: gp.DataCh, logger: gp.logger}
-		return s, nil
 	}
-
+	return err
 }
 
-func (gp *GraphPool) SetOption(option *common.GraphOption) error {
-	if gp.graphOption != nil {
-		return nil
+func (d *driver) openSession(pool *graph.ConnectionPool) (*graph.Session, error) {
+	sess, err := pool.GetSession(d.option.Username, d.option.Password)
+	if err != nil {
+		return nil, err
 	}
-	gp.graphOption = common.MakeDefaultOption(option)
-	if err := common.ValidateOption(gp.graphOption); err != nil {
-		return err
+	if _, err := sess.Execute(fmt.Sprintf("USE %s", d.option.Space)); err != nil {
+		sess.Release()
+		return nil, err
 	}
-	bs, _ := json.Marshal(gp.graphOption)
-	gp.logger.Debug(fmt.Sprintf("testing option: %s\n", bs))
-	return nil
-}
-
-func (gc *GraphClient) Open() error {
-	// nebula-go no need to open
-	return nil
-}
-
-func (gc *GraphClient) Close() error {
-	gc.Client.Release()
-	return nil
+	return sess, nil
 }
 
-// GetData get data from csv reader
-func (gc *GraphClient) GetData() (common.Data, error) {
-	if gc.DataCh != nil && len(gc.DataCh) != 0 {
-		if d, ok := <-gc.DataCh; ok {
-			return d, nil
-		}
+// Host returns the host of the session in the connection policy,
+// or "" in the session policy, since the session pool balances the hosts inside.
+func (c *conn) Host() string {
+	d := c.driver
+	if d.sessPool != nil || len(d.hosts) == 0 {
+		return ""
 	}
-	return nil, fmt.Errorf("no Data at all")
+	return d.hosts[c.index].String()
 }
 
-func (gc *GraphClient) executeRetry(stmt string) (*graph.ResultSet, error) {
-	// retry only when execution error
-	// if other errors, e.g. SemanticError, would return directly
-	var (
-		resp *graph.ResultSet
-		err  error
-	)
-	start := time.Now()
-	for i := 0; i < gc.Pool.graphOption.RetryTimes+1; i++ {
-		if gc.Client != nil {
-			resp, err = gc.Client.Execute(stmt)
-		} else {
-			resp, err = gc.Pool.sessPool.Execute(stmt)
+// Execute converts the parameters from js, and executes the statement until it is done or ctx is done.
+func (c *conn) Execute(ctx context.Context, stmt string, params map[string]any) (common.Result, error) {
+	var err error
+	if params != nil {
+		if params, err = toNebulaParams(params); err != nil {
+			return nil, fmt.Errorf("%w: %s", common.ErrInvalidParams, err.Error())
 		}
-		if gc.Pool.graphOption.RetryIntervalUs != 0 &&
-			time.Since(start).Microseconds() > int64(gc.Pool.graphOption.RetryTimeoutUs) {
-			return resp, fmt.Errorf("retry timeout")
-		}
-		if err != nil {
-			gc.logger.Warn(fmt.Sprintf("execute error: %s", err.Error()))
-			continue
-		}
-
-		graphErr := resp.GetErrorCode()
-		if graphErr == graph.ErrorCode_SUCCEEDED {
-			return resp, nil
+	}
+	if c.executor == nil {
+		if err := c.open(); err != nil {
+			return nil, err
 		}
-		// only retry for execution error
-		if graphErr != graph.ErrorCode_E_EXECUTION_ERROR {
-			break
+	}
+	executor, release := c.executor, c.release
+	done := make(chan executeResult, 1)
+	go func() {
+		rs, err := executor.ExecuteWithParameter(stmt, params)
+		done <- executeResult{rs: rs, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			if c.driver.sessPool == nil {
+				// the session is broken, e.g. the graphd is down, get one from the next host next time.
+				_ = c.Close()
+				c.index = (c.index + 1) % len(c.driver.connPools)
+			}
+			return nil, res.err
 		}
-		<-time.After(time.Duration(gc.Pool.graphOption.RetryIntervalUs) * time.Microsecond)
+		return &Response{ResultSet: res.rs}, nil
+	case <-ctx.Done():
+		// nebula-go could not cancel the statement, and the session is busy until it is done,
+		// so it is released in the background, and a new one is used for the next statement.
+		c.executor, c.release = nil, nil
+		go func() {
+			<-done
+			release()
+		}()
+		return nil, ctx.Err()
 	}
-	return resp, err
 }
 
-// Execute executes nebula query
-func (gc *GraphClient) Execute(stmt string) (common.IGraphResponse, error) {
-	stmt = common.ProcessStmt(stmt)
-	start := time.Now()
-	var (
-		o      *output
-		result common.IGraphResponse
-	)
-	resp, err := gc.executeRetry(stmt)
-	if err != nil {
-		// to summary the error, should validate the response is nil or not in js.
-		o = &output{
-			timeStamp:    start.Unix(),
-			nGQL:         stmt,
-			latency:      0,
-			responseTime: 0,
-			isSucceed:    false,
-			rows:         0,
-			errorMsg:     err.Error(),
-			firstRecord:  "",
-		}
-		result = nil
-	} else {
-		o = &output{
-			timeStamp:    start.Unix(),
-			nGQL:         stmt,
-			latency:      resp.GetLatency(),
-			responseTime: int32(time.Since(start) / 1000),
-			isSucceed:    resp.GetErrorCode() == graph.ErrorCode_SUCCEEDED,
-			rows:         int32(resp.GetRowSize()),
-			errorMsg:     resp.GetErrorMsg(),
-			firstRecord:  "",
-		}
-		result = &Response{ResultSet: resp, ResponseTime: o.responseTime}
-	}
-	if gc.Pool.OutputCh == nil {
-		return result, nil
+func (c *conn) Close() error {
+	if c.release != nil {
+		c.release()
+		c.executor, c.release = nil, nil
 	}
+	return nil
+}
 
-	if resp != nil {
-		var fr []string
-		columns := resp.GetColSize()
-		if o.rows != 0 {
-			r, err := resp.GetRowValuesByIndex(0)
-			if err != nil {
-				return nil, err
-			}
-			for i := 0; i < columns; i++ {
-				v, err := r.GetValueByIndex(i)
-				if err != nil {
-					return nil, err
-				}
-				fr = append(fr, v.String())
-			}
-		}
-		o.firstRecord = strings.Join(fr, "|")
-	}
+func (l *nebulaLogger) Info(msg string) {
+	l.Infof("%s", msg)
+}
 
-	select {
-	case gc.Pool.OutputCh <- formatOutput(o):
-	// abandon if the output chan is full.
-	default:
+func (l *nebulaLogger) Warn(msg string) {
+	l.Warnf("%s", msg)
+}
 
-	}
-	return result, nil
+func (l *nebulaLogger) Error(msg string) {
+	l.Errorf("%s", msg)
 }
 
-// GetResponseTime GetResponseTime
-func (r *Response) GetResponseTime() int32 {
-	return r.ResponseTime
+func (l *nebulaLogger) Fatal(msg string) {
+	l.Errorf("%s", msg)
 }
 
 // IsSucceed IsSucceed
@@ -476,3 +330,69 @@ func (r *Response) GetRowSize() int32 {
 	}
 	return 0
 }
+
+// GetErrorCode returns the name of the error code, e.g. SUCCEEDED, E_SEMANTIC_ERROR
+func (r *Response) GetErrorCode() string {
+	if r.ResultSet == nil {
+		return ""
+	}
+	return nebula.ErrorCode(r.ResultSet.GetErrorCode()).String()
+}
+
+// GetErrorMsg GetErrorMsg
+func (r *Response) GetErrorMsg() string {
+	if r.ResultSet == nil {
+		return ""
+	}
+	return r.ResultSet.GetErrorMsg()
+}
+
+// GetColumnNames GetColumnNames
+func (r *Response) GetColumnNames() []string {
+	if r.ResultSet == nil {
+		return nil
+	}
+	return r.ResultSet.GetColNames()
+}
+
+// GetRows returns all the rows, the values are converted to js values.
+func (r *Response) GetRows() ([][]any, error) {
+	if r.ResultSet == nil {
+		return nil, nil
+	}
+	rows := make([][]any, 0, r.ResultSet.GetRowSize())
+	for _, row := range r.ResultSet.GetRows() {
+		rows = append(rows, toJSList(row.Values))
+	}
+	return rows, nil
+}
+
+// GetRow returns the row at index.
+func (r *Response) GetRow(index int) ([]any, error) {
+	if r.ResultSet == nil || index < 0 || index >= r.ResultSet.GetRowSize() {
+		return nil, fmt.Errorf("row index out of range: %d", index)
+	}
+	return toJSList(r.ResultSet.GetRows()[index].Values), nil
+}
+
+// GetRecords returns all the rows as objects keyed by the column names.
+func (r *Response) GetRecords() ([]map[string]any, error) {
+	rows, err := r.GetRows()
+	if err != nil {
+		return nil, err
+	}
+	columns := r.GetColumnNames()
+	records := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, common.NewRecord(columns, row))
+	}
+	return records, nil
+}
+
+// Table returns all the rows as strings, without the column names.
+func (r *Response) Table() [][]string {
+	if r.ResultSet == nil {
+		return nil
+	}
+	return r.ResultSet.AsStringTable()[1:]
+}
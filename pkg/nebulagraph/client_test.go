@@ -0,0 +1,29 @@
+package nebulagraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+)
+
+func TestDriver(t *testing.T) {
+	d := &driver{}
+	err := d.Open(&common.GraphOption{
+		PoolOption: common.PoolOption{PoolPolicy: "unknown", Address: "127.0.0.1:9669", Space: "sf1"},
+	}, []common.HostAddress{{Host: "127.0.0.1", Port: 9669}}, nil)
+	assert.Error(t, err)
+	assert.NoError(t, d.Close())
+
+	// the invalid parameters are never sent.
+	c := &conn{release: func() {}}
+	_, err = c.Execute(context.Background(), "RETURN $p", map[string]any{"p": struct{}{}})
+	assert.ErrorIs(t, err, common.ErrInvalidParams)
+	assert.NoError(t, c.Close())
+
+	// the session of the connection policy knows its host.
+	d.hosts = []common.HostAddress{{Host: "192.168.8.6", Port: 9669}, {Host: "192.168.8.7", Port: 9669}}
+	c = &conn{driver: d, index: 1}
+	assert.Equal(t, "192.168.8.7:9669", c.Host())
+}
@@ -0,0 +1,305 @@
+package nebulagraph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+)
+
+type (
+	// InsertOption the options of inserting vertices or edges in batch.
+	InsertOption struct {
+		// Source the name of the data source, the default one if empty.
+		Source string `js:"source"`
+		// WithRank whether the rank of edge is the third column.
+		WithRank bool `js:"with_rank"`
+	}
+
+	// schema the types of the properties of a tag or an edge type, and the vid type of the space.
+	schema struct {
+		vidType string
+		props   map[string]string
+	}
+)
+
+// InsertVertices reads batchSize rows from the data source, and inserts them as vertices of the tag in one statement.
+// The first column of the row is the vid, and the other columns are the properties in order.
+// The values are escaped according to the types in the schema, the invalid rows are skipped, see Client.SkipRow.
+func (gc *GraphClient) InsertVertices(tag string, props []string, batchSize int, opts ...*InsertOption) (common.IGraphResponse, error) {
+	opt := getInsertOption(opts)
+	sc, err := gc.getSchema("TAG", tag)
+	if err != nil {
+		return nil, err
+	}
+	// check the properties before reading the rows, otherwise all of them would be skipped.
+	if err := sc.check(props); err != nil {
+		return nil, err
+	}
+	rows, err := gc.GetRows(opt.Source, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		v, err := sc.vertex(props, row)
+		if err != nil {
+			gc.SkipRow(tag, row, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	return gc.insert(fmt.Sprintf("INSERT VERTEX %s(%s) VALUES ", quoteName(tag), quoteNames(props)), tag, values, len(rows))
+}
+
+// InsertEdges reads batchSize rows from the data source, and inserts them as edges of the edge type in one statement.
+// The first two columns of the row are the source and destination vid, the third one is the rank if with_rank,
+// and the other columns are the properties in order.
+func (gc *GraphClient) InsertEdges(edgeType string, props []string, batchSize int, opts ...*InsertOption) (common.IGraphResponse, error) {
+	opt := getInsertOption(opts)
+	sc, err := gc.getSchema("EDGE", edgeType)
+	if err != nil {
+		return nil, err
+	}
+	if err := sc.check(props); err != nil {
+		return nil, err
+	}
+	rows, err := gc.GetRows(opt.Source, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		v, err := sc.edge(props, row, opt.WithRank)
+		if err != nil {
+			gc.SkipRow(edgeType, row, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	return gc.insert(fmt.Sprintf("INSERT EDGE %s(%s) VALUES ", quoteName(edgeType), quoteNames(props)), edgeType, values, len(rows))
+}
+
+func getInsertOption(opts []*InsertOption) *InsertOption {
+	for _, opt := range opts {
+		if opt != nil {
+			return opt
+		}
+	}
+	return &InsertOption{}
+}
+
+// insert executes the insert statement of the values, and pushes the number of inserted rows if succeeded.
+// It fails if all the rows read are skipped.
+func (gc *GraphClient) insert(prefix, schemaName string, values []string, rows int) (common.IGraphResponse, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("all the %d rows of %s are skipped", rows, schemaName)
+	}
+	resp, err := gc.Execute(prefix + strings.Join(values, ", "))
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsSucceed() {
+		gc.PushInserted(schemaName, len(values))
+	}
+	return resp, nil
+}
+
+// getSchema gets the schema of the tag or edge type, which is cached in the driver of the pool.
+func (gc *GraphClient) getSchema(kind, name string) (*schema, error) {
+	d := gc.driver
+	key := kind + " " + name
+	d.schemaMutex.Lock()
+	defer d.schemaMutex.Unlock()
+	if sc, ok := d.schemas[key]; ok {
+		return sc, nil
+	}
+	resp, err := gc.describe(fmt.Sprintf("DESCRIBE SPACE %s", quoteName(d.option.Space)))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSucceed() {
+		return nil, fmt.Errorf("failed to describe space: %s", resp.GetErrorMsg())
+	}
+	vidTypes, err := resp.GetValuesByColName("Vid Type")
+	if err != nil || len(vidTypes) == 0 {
+		return nil, fmt.Errorf("failed to get the vid type of space %s", d.option.Space)
+	}
+	vidType, err := vidTypes[0].AsString()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = gc.describe(fmt.Sprintf("DESCRIBE %s %s", kind, quoteName(name)))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSucceed() {
+		return nil, fmt.Errorf("failed to describe %s %s: %s", strings.ToLower(kind), name, resp.GetErrorMsg())
+	}
+	fields, err := resp.GetValuesByColName("Field")
+	if err != nil {
+		return nil, err
+	}
+	types, err := resp.GetValuesByColName("Type")
+	if err != nil {
+		return nil, err
+	}
+	sc := &schema{vidType: vidType, props: make(map[string]string, len(fields))}
+	for i := range fields {
+		f, err := fields[i].AsString()
+		if err != nil {
+			return nil, err
+		}
+		t, err := types[i].AsString()
+		if err != nil {
+			return nil, err
+		}
+		sc.props[f] = t
+	}
+	if d.schemas == nil {
+		d.schemas = make(map[string]*schema)
+	}
+	d.schemas[key] = sc
+	return sc, nil
+}
+
+// describe executes the DESCRIBE statement without any report.
+func (gc *GraphClient) describe(stmt string) (*Response, error) {
+	r, err := gc.Run(stmt, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.(*Response), nil
+}
+
+// check returns an error if any of the properties is not in the schema.
+func (sc *schema) check(props []string) error {
+	for _, p := range props {
+		if _, ok := sc.props[p]; !ok {
+			return fmt.Errorf("unknown property: %s", p)
+		}
+	}
+	return nil
+}
+
+// vertex returns the vertex of the row in the insert statement, e.g. 1:("Tom", 18).
+func (sc *schema) vertex(props []string, row common.Data) (string, error) {
+	if len(row) < len(props)+1 {
+		return "", fmt.Errorf("expect %d columns, but got %d", len(props)+1, len(row))
+	}
+	vid, err := toNGQLLiteral(row[0], sc.vidType)
+	if err != nil {
+		return "", err
+	}
+	pv, err := sc.values(props, row[1:])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:(%s)", vid, pv), nil
+}
+
+// edge returns the edge of the row in the insert statement, e.g. 1->2@0:("2010-02-14").
+func (sc *schema) edge(props []string, row common.Data, withRank bool) (string, error) {
+	keyColumns := 2
+	if withRank {
+		keyColumns = 3
+	}
+	if len(row) < len(props)+keyColumns {
+		return "", fmt.Errorf("expect %d columns, but got %d", len(props)+keyColumns, len(row))
+	}
+	src, err := toNGQLLiteral(row[0], sc.vidType)
+	if err != nil {
+		return "", err
+	}
+	dst, err := toNGQLLiteral(row[1], sc.vidType)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s->%s", src, dst)
+	if withRank {
+		rank, err := strconv.ParseInt(row[2], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid rank: %s", row[2])
+		}
+		key = fmt.Sprintf("%s@%d", key, rank)
+	}
+	pv, err := sc.values(props, row[keyColumns:])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:(%s)", key, pv), nil
+}
+
+// values returns the values of the properties joined by comma.
+func (sc *schema) values(props []string, columns []string) (string, error) {
+	values := make([]string, 0, len(props))
+	for i, p := range props {
+		t, ok := sc.props[p]
+		if !ok {
+			return "", fmt.Errorf("unknown property: %s", p)
+		}
+		v, err := toNGQLLiteral(columns[i], t)
+		if err != nil {
+			return "", fmt.Errorf("invalid value of property %s: %w", p, err)
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+// toNGQLLiteral converts the csv value to the nGQL literal of the type, e.g. int64, fixed_string(32), datetime.
+// The empty value is NULL except for the string types.
+func toNGQLLiteral(s, typ string) (string, error) {
+	t := strings.ToLower(typ)
+	if i := strings.Index(t, "("); i >= 0 {
+		t = t[:i]
+	}
+	if s == "" && t != "string" && t != "fixed_string" {
+		return "NULL", nil
+	}
+	switch t {
+	case "string", "fixed_string":
+		return common.QuoteString(s), nil
+	case "int64", "int32", "int16", "int8":
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid %s: %s", t, s)
+		}
+		return s, nil
+	case "float", "double":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return "", fmt.Errorf("invalid %s: %s", t, s)
+		}
+		return s, nil
+	case "bool":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid bool: %s", s)
+		}
+		return strconv.FormatBool(b), nil
+	case "timestamp":
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return s, nil
+		}
+		return fmt.Sprintf("timestamp(%s)", common.QuoteString(s)), nil
+	case "date", "time", "datetime":
+		return fmt.Sprintf("%s(%s)", t, common.QuoteString(s)), nil
+	case "geography":
+		return fmt.Sprintf("ST_GeogFromText(%s)", common.QuoteString(s)), nil
+	default:
+		return "", fmt.Errorf("unsupported type: %s", typ)
+	}
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, quoteName(n))
+	}
+	return strings.Join(quoted, ", ")
+}
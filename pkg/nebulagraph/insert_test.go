@@ -0,0 +1,73 @@
+package nebulagraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+)
+
+func TestToNGQLLiteral(t *testing.T) {
+	cases := []struct {
+		value string
+		typ   string
+		want  string
+	}{
+		{"933", "INT64", "933"},
+		{"933", "FIXED_STRING(32)", `"933"`},
+		{"a\"b\\c\n", "string", `"a\"b\\c\n"`},
+		{"", "string", `""`},
+		{"", "int32", "NULL"},
+		{"1.5", "double", "1.5"},
+		{"TRUE", "bool", "true"},
+		{"2010-02-14T01:51:21.746", "datetime", `datetime("2010-02-14T01:51:21.746")`},
+		{"1989-12-03", "date", `date("1989-12-03")`},
+		{"1265881881", "timestamp", "1265881881"},
+		{"POINT(1 2)", "geography(point)", `ST_GeogFromText("POINT(1 2)")`},
+	}
+	for _, c := range cases {
+		got, err := toNGQLLiteral(c.value, c.typ)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, got)
+	}
+	_, err := toNGQLLiteral("a", "int64")
+	assert.Error(t, err)
+	_, err = toNGQLLiteral("a", "list")
+	assert.Error(t, err)
+}
+
+func TestSchemaValues(t *testing.T) {
+	sc := &schema{vidType: "INT64", props: map[string]string{"name": "string", "age": "int8"}}
+	v, err := sc.values([]string{"name", "age"}, []string{"Tom", "18"})
+	assert.NoError(t, err)
+	assert.Equal(t, `"Tom", 18`, v)
+	_, err = sc.values([]string{"email"}, []string{"a"})
+	assert.Error(t, err)
+	assert.NoError(t, sc.check([]string{"name", "age"}))
+	assert.Error(t, sc.check([]string{"name", "email"}))
+}
+
+func TestSchemaRows(t *testing.T) {
+	sc := &schema{vidType: "INT64", props: map[string]string{"name": "string", "age": "int8"}}
+	v, err := sc.vertex([]string{"name", "age"}, common.Data{"1", "Tom", "18"})
+	assert.NoError(t, err)
+	assert.Equal(t, `1:("Tom", 18)`, v)
+	// the invalid rows are reported one by one, rather than failing the batch.
+	_, err = sc.vertex([]string{"name", "age"}, common.Data{"1", "Tom"})
+	assert.Error(t, err)
+	_, err = sc.vertex([]string{"name", "age"}, common.Data{"a", "Tom", "18"})
+	assert.Error(t, err)
+	_, err = sc.vertex([]string{"name", "age"}, common.Data{"1", "Tom", "x"})
+	assert.Error(t, err)
+
+	v, err = sc.edge([]string{"age"}, common.Data{"1", "2", "18"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, `1->2:(18)`, v)
+	v, err = sc.edge([]string{"age"}, common.Data{"1", "2", "3", "18"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, `1->2@3:(18)`, v)
+	_, err = sc.edge([]string{"age"}, common.Data{"1", "2", "x", "18"}, true)
+	assert.Error(t, err)
+	_, err = sc.edge([]string{"age"}, common.Data{"1", "2"}, false)
+	assert.Error(t, err)
+}
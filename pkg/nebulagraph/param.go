@@ -0,0 +1,76 @@
+package nebulagraph
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// toNebulaParams converts the parameters from js to the values which nebula-go accepts.
+func toNebulaParams(params map[string]any) (map[string]any, error) {
+	res := make(map[string]any, len(params))
+	for k, v := range params {
+		nv, err := toNebulaParam(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %s: %w", k, err)
+		}
+		res[k] = nv
+	}
+	return res, nil
+}
+
+func toNebulaParam(v any) (any, error) {
+	switch val := v.(type) {
+	case nil, bool, string, int, float32, float64:
+		return val, nil
+	case int8:
+		return int(val), nil
+	case int16:
+		return int(val), nil
+	case int32:
+		return int(val), nil
+	case int64:
+		return int(val), nil
+	case uint8:
+		return int(val), nil
+	case uint16:
+		return int(val), nil
+	case uint32:
+		return int(val), nil
+	case uint:
+		return toNebulaParam(uint64(val))
+	case uint64:
+		// the integers in nebula are int64.
+		if val > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows int64", val)
+		}
+		return int(val), nil
+	case time.Time:
+		t := val.UTC()
+		return nebula.DateTime{
+			Year:     int16(t.Year()),
+			Month:    int8(t.Month()),
+			Day:      int8(t.Day()),
+			Hour:     int8(t.Hour()),
+			Minute:   int8(t.Minute()),
+			Sec:      int8(t.Second()),
+			Microsec: int32(t.Nanosecond() / 1000),
+		}, nil
+	case []any:
+		list := make([]any, 0, len(val))
+		for _, e := range val {
+			ne, err := toNebulaParam(e)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, ne)
+		}
+		return list, nil
+	case map[string]any:
+		return toNebulaParams(val)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+}
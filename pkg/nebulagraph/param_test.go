@@ -0,0 +1,53 @@
+package nebulagraph
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestToNebulaParams(t *testing.T) {
+	params, err := toNebulaParams(map[string]any{
+		"n":   nil,
+		"b":   true,
+		"s":   "a",
+		"i":   1,
+		"i8":  int8(-8),
+		"i64": int64(64),
+		"u":   uint(1),
+		"u8":  uint8(8),
+		"u32": uint32(32),
+		"u64": uint64(math.MaxInt64),
+		"f":   1.5,
+		"t":   time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		"l":   []any{int64(1), "a"},
+		"m":   map[string]any{"k": uint64(2)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"n":   nil,
+		"b":   true,
+		"s":   "a",
+		"i":   1,
+		"i8":  -8,
+		"i64": 64,
+		"u":   1,
+		"u8":  8,
+		"u32": 32,
+		"u64": math.MaxInt64,
+		"f":   1.5,
+		"t":   nebula.DateTime{Year: 2024, Month: 1, Day: 2, Hour: 3, Minute: 4, Sec: 5, Microsec: 6},
+		"l":   []any{1, "a"},
+		"m":   map[string]any{"k": 2},
+	}, params)
+
+	_, err = toNebulaParams(map[string]any{"u64": uint64(math.MaxUint64)})
+	assert.Error(t, err)
+	_, err = toNebulaParams(map[string]any{"l": []any{struct{}{}}})
+	assert.Error(t, err)
+	_, err = toNebulaParams(map[string]any{"f": func() {}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package nebulagraph
+
+import (
+	"fmt"
+
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// toJSValue converts the nebula value to the value which could be used in js.
+func toJSValue(v *nebula.Value) any {
+	if v == nil {
+		return nil
+	}
+	switch {
+	case v.IsSetNVal():
+		return nil
+	case v.IsSetBVal():
+		return *v.BVal
+	case v.IsSetIVal():
+		return *v.IVal
+	case v.IsSetFVal():
+		return *v.FVal
+	case v.IsSetSVal():
+		return string(v.SVal)
+	case v.IsSetDVal():
+		d := v.DVal
+		return common.NewDate(int(d.Year), int(d.Month), int(d.Day))
+	case v.IsSetTVal():
+		t := v.TVal
+		return common.NewTime(int(t.Hour), int(t.Minute), int(t.Sec), int(t.Microsec))
+	case v.IsSetDtVal():
+		dt := v.DtVal
+		return common.NewDateTime(int(dt.Year), int(dt.Month), int(dt.Day),
+			int(dt.Hour), int(dt.Minute), int(dt.Sec), int(dt.Microsec))
+	case v.IsSetVVal():
+		return toJSVertex(v.VVal)
+	case v.IsSetEVal():
+		e := v.EVal
+		return common.NewEdge(toJSValue(e.Src), toJSValue(e.Dst), string(e.Name), e.Ranking, toJSProps(e.Props))
+	case v.IsSetPVal():
+		return toJSPath(v.PVal)
+	case v.IsSetLVal():
+		return toJSList(v.LVal.Values)
+	case v.IsSetUVal():
+		return toJSList(v.UVal.Values)
+	case v.IsSetMVal():
+		return toJSProps(v.MVal.Kvs)
+	case v.IsSetDuVal():
+		du := v.DuVal
+		return fmt.Sprintf("P%dMT%d.%06dS", du.Months, du.Seconds, du.Microseconds)
+	case v.IsSetGgVal():
+		return v.GgVal.String()
+	default:
+		return nil
+	}
+}
+
+func toJSList(values []*nebula.Value) []any {
+	list := make([]any, 0, len(values))
+	for _, v := range values {
+		list = append(list, toJSValue(v))
+	}
+	return list
+}
+
+func toJSProps(props map[string]*nebula.Value) map[string]any {
+	m := make(map[string]any, len(props))
+	for k, v := range props {
+		m[k] = toJSValue(v)
+	}
+	return m
+}
+
+func toJSVertex(v *nebula.Vertex) map[string]any {
+	if v == nil {
+		return nil
+	}
+	tags := make([]string, 0, len(v.Tags))
+	props := make(map[string]map[string]any, len(v.Tags))
+	for _, t := range v.Tags {
+		tags = append(tags, string(t.Name))
+		props[string(t.Name)] = toJSProps(t.Props)
+	}
+	return common.NewVertex(toJSValue(v.Vid), tags, props)
+}
+
+func toJSPath(p *nebula.Path) map[string]any {
+	nodes := []any{toJSVertex(p.Src)}
+	relationships := make([]any, 0, len(p.Steps))
+	src := p.Src
+	for _, step := range p.Steps {
+		from, to := src, step.Dst
+		// the negative type means the step is reversed.
+		if step.Type < 0 {
+			from, to = to, from
+		}
+		relationships = append(relationships, common.NewEdge(
+			toJSValue(from.GetVid()),
+			toJSValue(to.GetVid()),
+			string(step.Name),
+			step.Ranking,
+			toJSProps(step.Props),
+		))
+		nodes = append(nodes, toJSVertex(step.Dst))
+		src = step.Dst
+	}
+	return common.NewPath(nodes, relationships)
+}
@@ -0,0 +1,55 @@
+package nebulagraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestToJSValue(t *testing.T) {
+	vid := func(id int64) *nebula.Value {
+		return &nebula.Value{IVal: &id}
+	}
+	name := "Tom"
+	age := int64(18)
+	v1 := &nebula.Vertex{
+		Vid: vid(1),
+		Tags: []*nebula.Tag{{
+			Name:  []byte("Person"),
+			Props: map[string]*nebula.Value{"name": {SVal: []byte(name)}, "age": {IVal: &age}},
+		}},
+	}
+	v2 := &nebula.Vertex{Vid: vid(2)}
+	path := &nebula.Value{PVal: &nebula.Path{
+		Src: v1,
+		Steps: []*nebula.Step{{
+			Dst:     v2,
+			Type:    -1,
+			Name:    []byte("KNOWS"),
+			Ranking: 0,
+		}},
+	}}
+
+	p := toJSValue(path).(map[string]any)
+	nodes := p["nodes"].([]any)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, int64(1), nodes[0].(map[string]any)["vid"])
+	assert.Equal(t, []string{"Person"}, nodes[0].(map[string]any)["tags"])
+	assert.Equal(t, map[string]map[string]any{"Person": {"name": "Tom", "age": int64(18)}},
+		nodes[0].(map[string]any)["properties"])
+	rel := p["relationships"].([]any)[0].(map[string]any)
+	// the reversed step is from the dst to the src
+	assert.Equal(t, int64(2), rel["src"])
+	assert.Equal(t, int64(1), rel["dst"])
+	assert.Equal(t, "KNOWS", rel["type"])
+
+	dt := &nebula.Value{DtVal: &nebula.DateTime{Year: 2024, Month: 1, Day: 2, Hour: 3}}
+	assert.Equal(t, 2024, toJSValue(dt).(map[string]any)["year"])
+	assert.Equal(t, 3, toJSValue(dt).(map[string]any)["hour"])
+
+	list := &nebula.Value{LVal: &nebula.NList{Values: []*nebula.Value{vid(1), {SVal: []byte("a")}}}}
+	assert.Equal(t, []any{int64(1), "a"}, toJSValue(list))
+	null := nebula.NullType___NULL__
+	assert.Nil(t, toJSValue(&nebula.Value{NVal: &null}))
+}
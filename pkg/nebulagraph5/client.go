@@ -1,203 +1,141 @@
 package nebulagraph5
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"math"
-	"strconv"
-	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vesoft-inc/k6-plugin/pkg/common"
 
 	nebula "github.com/vesoft-inc/nebula-go/v5"
+	nerrors "github.com/vesoft-inc/nebula-go/v5/pkg/errors"
 	"github.com/vesoft-inc/nebula-go/v5/pkg/types"
 )
 
 type (
 	// GraphPool nebula connection pool
-	GraphPool struct {
-		mutex             sync.Mutex
-		DataCh            chan common.Data
-		OutputCh          chan []string
-		Version           string
-		csvStrategy       csvReaderStrategy
-		initialized       bool
-		pool              types.Pool
-		clients           []*GraphClient
-		channelBufferSize int
-		Hosts             []string
-		csvReader         common.ICsvReader
-		graphOption       *common.GraphOption
-		maxLifeTime       time.Duration
-		logger            logger
-	}
-
-	logger interface {
-		Infof(msg string, args ...any)
-		Warnf(msg string, args ...any)
-		Debugf(msg string, args ...any)
-		Errorf(msg string, args ...any)
-	}
-
-	graphClientGetter  func(endpoint, username, password string, timeout time.Duration) (types.Client, error)
-	GraphClientFactory struct{}
-
-	// GraphClient a wrapper for nebula client, could read data from DataCh
+	GraphPool = common.Pool
+
+	// driver connects to the NebulaGraph 5.x by the pool of nebula-go, or by a client per session, see pool_policy.
+	driver struct {
+		option      *common.GraphOption
+		hosts       []common.HostAddress
+		pool        types.Pool
+		maxLifeTime time.Duration
+		logger      common.Logger
+		// next the host of the next client of the client policy, the clients are spread over the hosts in turn.
+		next uint32
+	}
+
+	// conn holds a client for a session, which is got lazily, and is replaced once it is broken
+	// or has been used for max_life_time.
+	// The client of the client policy connects to a host, and moves to the next host once it is broken.
+	conn struct {
+		driver *driver
+		client types.Client
+		since  time.Time
+		// index the index of the host of the client policy.
+		index int
+		// open opens the client of the session opened by OpenAddress, instead of pool_policy.
+		open    func() (types.Client, error)
+		address string
+	}
+
+	// sharedConn borrows a client from the pool for every statement, like the session pool in 3.x.
+	sharedConn struct {
+		driver *driver
+	}
+
+	// GraphClient a wrapper for nebula client, adds OpenAddress to the session.
 	GraphClient struct {
-		Session  types.Client
-		Pool     *GraphPool
-		DataCh   chan common.Data
-		username string
-		password string
-		since    time.Time
+		*common.Client
+		driver *driver
+		opened bool
 	}
 
+	// Deprecated: GraphClientFactory the sessions should be got from the pool,
+	// GetClient returns a session without a pool, which could not execute any statement.
+	GraphClientFactory struct{}
+
 	// Response a wrapper for nebula resultSet
 	Response struct {
-		ResultSet    types.Result
-		err          error
-		ResponseTime int32
-	}
-
-	csvReaderStrategy int
-
-	output struct {
-		timeStamp    int64
-		nGQL         string
-		latency      int64
-		responseTime int32
-		isSucceed    bool
-		rows         int32
-		errorMsg     string
-		firstRecord  string
+		ResultSet types.Result
+		common.ResultMeta
+		err  error
+		rows []types.Row
 	}
 )
 
+var _ common.Driver = &driver{}
 var _ common.IGraphClient = &GraphClient{}
-var _ common.IGraphClientPool = &GraphPool{}
-
-const (
-	// AllInOne read csv sequentially
-	AllInOne csvReaderStrategy = iota
-	// Separate read csv concurrently
-	Separate
-)
-
-func formatOutput(o *output) []string {
-	return []string{
-		strconv.FormatInt(o.timeStamp, 10),
-		o.nGQL,
-		strconv.Itoa(int(o.latency)),
-		strconv.Itoa(int(o.responseTime)),
-		strconv.FormatBool(o.isSucceed),
-		strconv.Itoa(int(o.rows)),
-		o.firstRecord,
-		o.errorMsg,
-	}
-}
-
-var outputHeader []string = []string{
-	"timestamp",
-	"nGQL",
-	"latency",
-	"responseTime",
-	"isSucceed",
-	"rows",
-	"firstRecord",
-	"errorMsg",
-}
+var _ common.Result = &Response{}
 
 // NewNebulaGraph New for k6 initialization.
 func NewNebulaGraph() *GraphPool {
-	return &GraphPool{}
+	return common.NewPool(&driver{})
 }
 
+// Deprecated: NewGraphClientFactory the sessions should be got from the pool.
 func NewGraphClientFactory() *GraphClientFactory {
 	return &GraphClientFactory{}
 }
 
+// Deprecated: GetClient returns a session without a pool.
 func (gf *GraphClientFactory) GetClient() *GraphClient {
-	return &GraphClient{}
+	return &GraphClient{Client: &common.Client{}, driver: &driver{}}
 }
 
-func (gp *GraphPool) SetOption(option *common.GraphOption) error {
-	if gp.graphOption != nil {
+// Open opens the pool of nebula-go by pool_policy, there is no pool for the client policy.
+func (d *driver) Open(opt *common.GraphOption, hosts []common.HostAddress, l common.Logger) error {
+	d.option = opt
+	d.hosts = hosts
+	d.logger = l
+	d.maxLifeTime = getMaxLifeTime(opt.ExtraOptions)
+	if opt.UseHttp {
+		return fmt.Errorf("use_http is not supported in 5.x, the clients always connect by gRPC over HTTP/2")
+	}
+	switch opt.PoolPolicy {
+	case string(common.ConnectionPool), string(common.SessionPool):
+	case string(common.DedicatedClient):
 		return nil
+	default:
+		return fmt.Errorf("invalid pool policy: %s, need connection, session or client", opt.PoolPolicy)
 	}
-	gp.graphOption = common.MakeDefaultOption(option)
-	if err := common.ValidateOption(gp.graphOption); err != nil {
-		return err
-	}
-	bs, _ := json.Marshal(gp.graphOption)
-	gp.logger.Infof("testing option: %s\n", bs)
-	return nil
-}
-
-// Init initializes nebula pool with address and concurrent, by default the bufferSize is 20000
-func (gp *GraphPool) Init() (common.IGraphClientPool, error) {
-	gp.mutex.Lock()
-	defer gp.mutex.Unlock()
-	if gp.initialized {
-		return gp, nil
-	}
-	if err := gp.validate(gp.graphOption.Address); err != nil {
-		return nil, err
-	}
-	gp.Hosts = strings.Split(gp.graphOption.Address, ",")
-	if gp.graphOption.Output != "" {
-		channelBufferSize := gp.graphOption.OutputChannelSize
-		gp.OutputCh = make(chan []string, channelBufferSize)
-		writer := common.NewCsvWriter(gp.graphOption.Output, ",", outputHeader, gp.OutputCh)
-		if err := writer.WriteForever(); err != nil {
-			return nil, err
-		}
-	}
-	if gp.graphOption.CsvPath != "" {
-		gp.csvReader = common.NewCsvReader(
-			gp.graphOption.CsvPath,
-			gp.graphOption.CsvDelimiter,
-			gp.graphOption.CsvWithHeader,
-			gp.graphOption.CsvDataLimit,
-		)
-		gp.DataCh = make(chan common.Data, gp.graphOption.CsvChannelSize)
-		if err := gp.csvReader.ReadForever(gp.DataCh); err != nil {
-			return nil, err
-		}
-	}
-
 	options := []nebula.PoolOptionsFn{
-		nebula.WithPoolMaxOpenConns(gp.graphOption.MaxSize * 2),
-		nebula.WithPoolMinOpenConns(gp.graphOption.MinSize),
-	}
-	if gp.graphOption.RetryTimeoutUs <= 0 {
-		gp.graphOption.RetryTimeoutUs = math.MaxInt32
+		nebula.WithPoolMaxOpenConns(opt.MaxSize),
+		nebula.WithPoolMinOpenConns(opt.MinSize),
+		nebula.WithPoolRequestTimeout(time.Duration(opt.TimeoutUs) * time.Microsecond),
+		nebula.WithPoolMaxWait(1 * time.Minute),
+	}
+	if opt.IdleTimeUs > 0 {
+		// the pool closes the idle clients beyond the max idle ones periodically.
+		options = append(options,
+			nebula.WithPoolMaxIdleConns(opt.MinSize),
+			nebula.WithPoolTickerDuration(time.Duration(opt.IdleTimeUs)*time.Microsecond))
+	} else {
+		options = append(options, nebula.WithPoolMaxIdleConns(opt.MaxSize))
 	}
-	options = append(options, nebula.WithPoolRequestTimeout(time.Duration(gp.graphOption.TimeoutUs)*time.Microsecond))
-	if gp.graphOption.SslCaPemPath != "" {
+	if opt.SslCaPemPath != "" {
 		options = append(options, nebula.WithPoolTLS(
-			gp.graphOption.SslCaPemPath,
-			gp.graphOption.SslClientPemPath,
-			gp.graphOption.SslClientKeyPath,
+			opt.SslCaPemPath,
+			opt.SslClientPemPath,
+			opt.SslClientKeyPath,
 			false,
 		))
 	}
-	options = append(options, nebula.WithPoolMaxWait(1*time.Minute))
 	pool, err := nebula.NewNebulaPool(
-		gp.graphOption.Address,
-		gp.graphOption.Username,
-		gp.graphOption.Password,
+		opt.Address,
+		opt.Username,
+		opt.Password,
 		options...,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	gp.maxLifeTime = getMaxLifeTime(gp.graphOption.ExtraOptions)
-	gp.pool = pool
-	gp.clients = make([]*GraphClient, 0)
-	gp.initialized = true
-	return gp, nil
+	d.pool = pool
+	return nil
 }
 
 func getMaxLifeTime(extra any) time.Duration {
@@ -216,221 +154,246 @@ func getMaxLifeTime(extra any) time.Duration {
 	return 0
 }
 
-func (gp *GraphPool) validate(address string) error {
-	addrs := strings.Split(address, ",")
-	if len(addrs) == 0 {
-		return fmt.Errorf("Invalid address: %s", address)
-	}
-	for _, addr := range addrs {
-		hostAndPort := strings.Split(addr, ":")
-		if len(hostAndPort) != 2 {
-			return fmt.Errorf("Invalid address: %s", addr)
+// Conn returns a connection by pool_policy, the client policy connects at once,
+// and the connection policy gets its client from the pool on the first statement.
+func (d *driver) Conn() (common.Conn, error) {
+	switch d.option.PoolPolicy {
+	case string(common.SessionPool):
+		return &sharedConn{driver: d}, nil
+	case string(common.DedicatedClient):
+		index := int(atomic.AddUint32(&d.next, 1) - 1)
+		client, err := d.getClient(d.host(index))
+		if err != nil {
+			return nil, err
 		}
+		return &conn{driver: d, client: client, since: time.Now(), index: index}, nil
+	default:
+		return &conn{driver: d}, nil
 	}
-	return nil
 }
 
-// Close closes the nebula pool
-func (gp *GraphPool) Close() error {
-	gp.mutex.Lock()
-	defer gp.mutex.Unlock()
-	for _, client := range gp.clients {
-		client.Close()
+// host returns the host of the index in turn, or the address if there are no hosts.
+func (d *driver) host(index int) string {
+	if len(d.hosts) == 0 {
+		return d.option.Address
 	}
-	gp.pool.Close()
-	return nil
+	return d.hosts[index%len(d.hosts)].String()
 }
 
-// GetSession gets the session from pool
-func (gp *GraphPool) GetSession() (common.IGraphClient, error) {
-	gp.mutex.Lock()
-	defer gp.mutex.Unlock()
-	if !gp.initialized {
-		return nil, fmt.Errorf("GraphPool is not initialized, please call Init() first")
+// getClient gets a client from the pool, or opens a new one to the address if there is no pool.
+func (d *driver) getClient(address string) (types.Client, error) {
+	if d.pool != nil {
+		return d.pool.GetClient()
+	}
+	options := []nebula.ClientOptionsFn{
+		nebula.WithClientRequestTimeout(time.Duration(d.option.TimeoutUs) * time.Microsecond),
+	}
+	if d.option.SslCaPemPath != "" {
+		options = append(options, nebula.WithClientTLS(
+			d.option.SslCaPemPath,
+			d.option.SslClientPemPath,
+			d.option.SslClientKeyPath,
+			false,
+		))
 	}
+	return nebula.NewNebulaClient(address, d.option.Username, d.option.Password, options...)
+}
 
-	s := &GraphClient{Pool: gp, DataCh: gp.DataCh, since: time.Now()}
-	gp.clients = append(gp.clients, s)
-	return s, nil
+// putClient puts the client back to the pool for reuse, or closes it if it is discarded or there is no pool.
+func (d *driver) putClient(client types.Client, discard bool) {
+	if d.pool == nil {
+		_ = client.Close()
+		return
+	}
+	if discard {
+		_ = client.Close()
+	}
+	_ = d.pool.PutClient(client)
 }
 
-func (gc *GraphClient) Open() error {
-	return nil
+// RetryOn retries all the failed requests by default.
+func (d *driver) RetryOn() []string {
+	return []string{common.RetryNetwork, common.RetryServer}
+}
+
+// Wrap adds OpenAddress to the session.
+func (d *driver) Wrap(c *common.Client) common.IGraphClient {
+	return &GraphClient{Client: c, driver: d}
 }
 
+// OpenAddress connects the session to the address by its own client instead of pool_policy,
+// the async requests of the session connect to the address as well. The connect timeout is in seconds.
 func (gc *GraphClient) OpenAddress(address, username, password string, connectTimeout int) error {
-	if gc.Session != nil {
+	if gc.opened {
 		return fmt.Errorf("session already open")
 	}
-	connectTimeoutDuration := time.Duration(connectTimeout) * time.Second
-	client, err := nebula.NewNebulaClient(address, username, password,
-		nebula.WithClientConnectTimeout(connectTimeoutDuration),
-	)
+	d := gc.driver
+	open := func() (types.Client, error) {
+		options := []nebula.ClientOptionsFn{
+			nebula.WithClientConnectTimeout(time.Duration(connectTimeout) * time.Second),
+		}
+		if d.option != nil {
+			options = append(options,
+				nebula.WithClientRequestTimeout(time.Duration(d.option.TimeoutUs)*time.Microsecond))
+		}
+		return nebula.NewNebulaClient(address, username, password, options...)
+	}
+	err := gc.OpenConn(func() (common.Conn, error) {
+		client, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return &conn{driver: d, client: client, since: time.Now(), open: open, address: address}, nil
+	})
 	if err != nil {
 		return err
 	}
-	gc.Session = client
+	gc.opened = true
 	return nil
 }
 
-func (gc *GraphClient) Close() error {
-	if gc.Session == nil {
+func (d *driver) Close() error {
+	if d.pool == nil {
 		return nil
 	}
-	gc.Session.Close()
-	gc.Session = nil
-	return nil
+	err := d.pool.Close()
+	d.pool = nil
+	return err
 }
 
-// GetData get data from csv reader
-func (gc *GraphClient) GetData() (common.Data, error) {
-	if gc.DataCh != nil && len(gc.DataCh) != 0 {
-		if d, ok := <-gc.DataCh; ok {
-			return d, nil
-		}
+// renderStmt renders the parameters in the statement, if any.
+func renderStmt(stmt string, params map[string]any) (string, error) {
+	if params == nil {
+		return stmt, nil
+	}
+	stmt, err := renderParams(stmt, params)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", common.ErrInvalidParams, err.Error())
 	}
-	return nil, fmt.Errorf("no Data at all")
+	return stmt, nil
 }
 
-// Execute executes nebula query
-func (gc *GraphClient) Execute(stmt string) (common.IGraphResponse, error) {
-	var (
-		isSucceed  bool = true
-		errMessage string
-		err        error
-		resp       types.Result
-		rows       int32
-		latency    int64
-	)
-	stmt = common.ProcessStmt(stmt)
-	start := time.Now()
-	if gc.Pool.maxLifeTime > 0 && time.Since(gc.since) > gc.Pool.maxLifeTime {
-		gc.Pool.logger.Debugf("the client has been used for %v, which is longer than maxLifeTime %v, so we need to recreate it",
-			time.Since(gc.since), gc.Pool.maxLifeTime)
-		if gc.Session != nil {
-			gc.Session.Close()
-			gc.Pool.pool.PutClient(gc.Session)
-			sess, err := gc.Pool.pool.GetClient()
+// execute executes the statement by the client, and decodes all the rows, so that they could be read in js.
+// The error means the client is broken or ctx is done, and the failed statement is returned as the response.
+func execute(ctx context.Context, client types.Client, stmt string) (*Response, error) {
+	rs, err := client.ExecuteContext(ctx, stmt)
+	if err != nil && rs == nil {
+		return nil, fmt.Errorf("execute statement failed: %s, error: %w", stmt, err)
+	}
+	if err != nil {
+		return &Response{err: err}, nil
+	}
+	var rows []types.Row
+	if rs.RowSize() != 0 {
+		rows = make([]types.Row, 0, rs.RowSize())
+		for rs.HasNext() {
+			row, err := rs.Next()
 			if err != nil {
-
 				return nil, err
 			}
-			gc.Session = sess
+			rows = append(rows, row)
 		}
-		gc.since = time.Now()
 	}
-	resp, err = gc.executeWithRetry(stmt)
+	return &Response{ResultSet: rs, rows: rows}, nil
+}
 
+// Execute executes the statement by the client of the session.
+func (c *conn) Execute(ctx context.Context, stmt string, params map[string]any) (common.Result, error) {
+	stmt, err := renderStmt(stmt, params)
 	if err != nil {
-		isSucceed = false
-		errMessage = err.Error()
-	} else {
-		rows = int32(resp.RowSize())
-		latency = resp.Summary().TotalServerTimeUs()
-	}
-	var fr []string
-	var values []*nebula.NullValue
-	var anyValues []any
-	if rows != 0 {
-		// print the first row of the result
-		values = make([]*nebula.NullValue, 0, len(resp.Columns()))
-		anyValues = make([]any, 0, len(resp.Columns()))
-		for _ = range resp.Columns() {
-			value := &nebula.NullValue{}
-			values = append(values, value)
-			anyValues = append(anyValues, value)
-		}
-
-		if err := resp.Scan(anyValues...); err != nil {
+		return nil, err
+	}
+	d := c.driver
+	if c.client != nil && d.maxLifeTime > 0 && time.Since(c.since) > d.maxLifeTime {
+		d.logger.Debugf("the client has been used for %v, which is longer than maxLifeTime %v, so we need to recreate it",
+			time.Since(c.since), d.maxLifeTime)
+		c.release(true)
+	}
+	if c.client == nil || c.client.IsClosed() {
+		client, err := c.getClient()
+		if err != nil {
+			c.moveHost()
 			return nil, err
 		}
-
-		for _, v := range values {
-			if !v.Valid || v.Data == nil {
-				fr = append(fr, "NULL")
-			} else {
-				fr = append(fr, v.Data.String())
-			}
-		}
+		c.client = client
+		c.since = time.Now()
 	}
-	//TODO could add a flag to just decode the first row
-	if rows != 0 {
-		for resp.HasNext() {
-			if err := resp.Scan(anyValues...); err != nil {
-				return nil, err
-			}
+	r, err := execute(ctx, c.client, stmt)
+	if err != nil {
+		// the client is broken, or may be still busy with the statement, get a new one next time.
+		c.release(true)
+		if ctx.Err() == nil {
+			c.moveHost()
 		}
+		return nil, err
 	}
-	responseTime := int32(time.Since(start) / 1000)
-	// output
-	if gc.Pool.OutputCh != nil {
-		o := &output{
-			timeStamp:    start.Unix(),
-			nGQL:         stmt,
-			latency:      latency,
-			responseTime: responseTime,
-			isSucceed:    isSucceed,
-			rows:         rows,
-			errorMsg:     errMessage,
-			firstRecord:  strings.Join(fr, "|"),
-		}
-		select {
-		case gc.Pool.OutputCh <- formatOutput(o):
-		// abandon if the output chan is full.
-		default:
-			gc.Pool.logger.Warnf("output channel is full, abandon the output: %v\n", o)
-		}
+	return r, nil
+}
+
+// getClient gets the client to the address opened by OpenAddress, or the client by pool_policy.
+func (c *conn) getClient() (types.Client, error) {
+	if c.open != nil {
+		return c.open()
 	}
-	return &Response{ResultSet: resp, ResponseTime: responseTime, err: err}, nil
+	return c.driver.getClient(c.driver.host(c.index))
 }
 
-func (gc *GraphClient) executeWithRetry(stmt string) (types.Result, error) {
-	var (
-		err  error
-		resp types.Result
-	)
-	retryTimeout := time.Duration(gc.Pool.graphOption.RetryTimeoutUs) * time.Microsecond
-	if retryTimeout <= 0 {
-		retryTimeout = math.MaxInt64
-	}
-	start := time.Now()
-	for i := 0; i < gc.Pool.graphOption.RetryTimes+1; i++ {
-		if time.Now().Sub(start) > retryTimeout {
-			return nil, fmt.Errorf("execute statement timeout: %s, timeout: %v", stmt, retryTimeout)
-		}
-		if i > 0 {
-			gc.Pool.logger.Warnf("execute statement failed, retry %d time, error: %s\n", i, err.Error())
-		}
-		resp, err = gc.execute(stmt)
-		if err == nil {
-			return resp, nil
-		} else {
-			gc.Session.Close()
-			gc.Pool.pool.PutClient(gc.Session)
-		}
-		time.Sleep(time.Duration(gc.Pool.graphOption.RetryIntervalUs) * time.Microsecond)
+// Host returns the address opened by OpenAddress, the host of the client of the client policy,
+// or "" if the client is got from the pool, which balances the hosts inside.
+func (c *conn) Host() string {
+	if c.address != "" {
+		return c.address
+	}
+	if c.driver.pool != nil {
+		return ""
 	}
-	return nil, err
+	return c.driver.host(c.index)
 }
 
-func (gc *GraphClient) execute(stmt string) (types.Result, error) {
-	if gc.Session == nil || gc.Session.IsClosed() {
-		sess, err := gc.Pool.pool.GetClient()
-		if err != nil {
-			return nil, err
-		}
-		gc.Session = sess
+// moveHost moves the client of the client policy to the next host, e.g. the host is down.
+func (c *conn) moveHost() {
+	c.index++
+}
+
+// release gives up the client of the session, the client opened by OpenAddress is never put to the pool.
+func (c *conn) release(discard bool) {
+	if c.client == nil {
+		return
+	}
+	if c.open != nil {
+		_ = c.client.Close()
+	} else {
+		c.driver.putClient(c.client, discard)
+	}
+	c.client = nil
+}
+
+func (c *conn) Close() error {
+	c.release(false)
+	return nil
+}
+
+// Execute executes the statement by a client borrowed from the pool.
+func (c *sharedConn) Execute(ctx context.Context, stmt string, params map[string]any) (common.Result, error) {
+	stmt, err := renderStmt(stmt, params)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := gc.Session.Execute(stmt)
+	client, err := c.driver.getClient("")
 	if err != nil {
-		return nil, fmt.Errorf("execute statement failed: %s, error: %w", stmt, err)
+		return nil, err
+	}
+	r, err := execute(ctx, client, stmt)
+	c.driver.putClient(client, err != nil)
+	if err != nil {
+		return nil, err
 	}
-	return resp, nil
+	return r, nil
 }
 
-// GetResponseTime GetResponseTime
-func (r *Response) GetResponseTime() int32 {
-	return r.ResponseTime
+// Close does nothing, the clients are returned once the statements are done.
+func (c *sharedConn) Close() error {
+	return nil
 }
 
 // IsSucceed IsSucceed
@@ -456,3 +419,88 @@ func (r *Response) GetRowSize() int32 {
 	}
 	return 0
 }
+
+// GetErrorCode returns the GQLSTATUS of the error, e.g. 42001
+func (r *Response) GetErrorCode() string {
+	if r.err == nil {
+		return string(nerrors.ERROR_SUCCESSFUL_COMPLETION)
+	}
+	var ne *nerrors.NebulaError
+	if errors.As(r.err, &ne) {
+		return string(ne.Code())
+	}
+	return ""
+}
+
+// GetErrorMsg GetErrorMsg
+func (r *Response) GetErrorMsg() string {
+	if r.err == nil {
+		return ""
+	}
+	return r.err.Error()
+}
+
+// GetColumnNames GetColumnNames
+func (r *Response) GetColumnNames() []string {
+	if r.ResultSet == nil {
+		return nil
+	}
+	return r.ResultSet.Columns()
+}
+
+// GetRows returns all the rows, the values are converted to js values.
+func (r *Response) GetRows() ([][]any, error) {
+	rows := make([][]any, 0, len(r.rows))
+	for _, row := range r.rows {
+		jr, err := toJSList(row.Values())
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, jr)
+	}
+	return rows, nil
+}
+
+// GetRow returns the row at index.
+func (r *Response) GetRow(index int) ([]any, error) {
+	if index < 0 || index >= len(r.rows) {
+		return nil, fmt.Errorf("row index out of range: %d", index)
+	}
+	return toJSList(r.rows[index].Values())
+}
+
+// GetRecords returns all the rows as objects keyed by the column names.
+func (r *Response) GetRecords() ([]map[string]any, error) {
+	rows, err := r.GetRows()
+	if err != nil {
+		return nil, err
+	}
+	columns := r.GetColumnNames()
+	records := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, common.NewRecord(columns, row))
+	}
+	return records, nil
+}
+
+// Table returns all the rows as strings.
+func (r *Response) Table() [][]string {
+	table := make([][]string, 0, len(r.rows))
+	for _, row := range r.rows {
+		table = append(table, rowStrings(row))
+	}
+	return table
+}
+
+// rowStrings returns the values of the row as strings.
+func rowStrings(row types.Row) []string {
+	values := make([]string, 0, len(row.Values()))
+	for _, v := range row.Values() {
+		if v == nil || v.IsNull() {
+			values = append(values, "NULL")
+		} else {
+			values = append(values, v.String())
+		}
+	}
+	return values
+}
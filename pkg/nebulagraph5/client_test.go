@@ -0,0 +1,55 @@
+package nebulagraph5
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+	"github.com/vesoft-inc/nebula-go/v5/pkg/types"
+)
+
+func TestDriver(t *testing.T) {
+	d := &driver{}
+	opt := &common.GraphOption{
+		PoolOption: common.PoolOption{PoolPolicy: "unknown", Address: "127.0.0.1:9669", Space: "sf1"},
+	}
+	assert.Error(t, d.Open(opt, nil, nil))
+	opt.PoolPolicy, opt.UseHttp = string(common.ConnectionPool), true
+	assert.ErrorContains(t, d.Open(opt, nil, nil), "use_http is not supported")
+	opt.UseHttp = false
+	// there is no pool for the client policy, the sessions connect by themselves.
+	opt.PoolPolicy = string(common.DedicatedClient)
+	hosts := []common.HostAddress{{Host: "192.168.8.6", Port: 9669}, {Host: "192.168.8.7", Port: 9669}}
+	assert.NoError(t, d.Open(opt, hosts, nil))
+	assert.Nil(t, d.pool)
+	assert.NoError(t, d.Close())
+	// the client of the client policy knows its host, and moves to the next one once it is broken.
+	hc := &conn{driver: d, index: 1}
+	assert.Equal(t, "192.168.8.7:9669", hc.Host())
+	hc.moveHost()
+	assert.Equal(t, "192.168.8.6:9669", hc.Host())
+
+	// the invalid parameters are never sent.
+	c := &conn{driver: d}
+	_, err := c.Execute(context.Background(), "RETURN $p", map[string]any{"p": struct{}{}})
+	assert.ErrorIs(t, err, common.ErrInvalidParams)
+	assert.NoError(t, c.Close())
+	sc := &sharedConn{driver: d}
+	_, err = sc.Execute(context.Background(), "RETURN $p", map[string]any{"p": struct{}{}})
+	assert.ErrorIs(t, err, common.ErrInvalidParams)
+
+	// the session could connect to an address by itself.
+	gc := NewGraphClientFactory().GetClient()
+	assert.Error(t, gc.OpenAddress("127.0.0.1:1", "root", "nebula", 1))
+	assert.NoError(t, gc.Close())
+	hc = &conn{driver: d, address: "192.168.8.8:9669", open: func() (types.Client, error) { return nil, nil }}
+	assert.Equal(t, "192.168.8.8:9669", hc.Host())
+
+	r := &Response{err: fmt.Errorf("syntax error")}
+	assert.False(t, r.IsSucceed())
+	assert.Equal(t, "syntax error", r.GetErrorMsg())
+	assert.Equal(t, int32(0), r.GetRowSize())
+	assert.Empty(t, r.Table())
+}
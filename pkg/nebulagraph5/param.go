@@ -0,0 +1,177 @@
+package nebulagraph5
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// renderParams replaces the parameters, e.g. $id, in the statement with the literals of the values.
+// nebula-go v5 does not support executing with parameters, so the parameters are rendered in the client.
+// The parameters in quotes or not in params are kept as they are.
+func renderParams(stmt string, params map[string]any) (string, error) {
+	var (
+		sb    strings.Builder
+		quote rune
+		runes = []rune(stmt)
+	)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			sb.WriteRune(r)
+			if r == '\\' && i+1 < len(runes) {
+				i++
+				sb.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+			sb.WriteRune(r)
+		case r == '$':
+			j := i + 1
+			for j < len(runes) && isIdentRune(runes[j], j == i+1) {
+				j++
+			}
+			name := string(runes[i+1 : j])
+			v, ok := params[name]
+			if name == "" || !ok {
+				sb.WriteRune(r)
+				continue
+			}
+			literal, err := toLiteral(v)
+			if err != nil {
+				return "", fmt.Errorf("invalid parameter %s: %w", name, err)
+			}
+			sb.WriteString(literal)
+			i = j - 1
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+func isIdentRune(r rune, first bool) bool {
+	if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+		return true
+	}
+	return !first && r >= '0' && r <= '9'
+}
+
+func toLiteral(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int8:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint:
+		return toLiteral(uint64(val))
+	case uint64:
+		// the integers in nebula are int64.
+		if val > math.MaxInt64 {
+			return "", fmt.Errorf("%d overflows int64", val)
+		}
+		return strconv.FormatUint(val, 10), nil
+	case float32:
+		return formatFloat(float64(val))
+	case float64:
+		return formatFloat(val)
+	case string:
+		return quoteString(val), nil
+	case time.Time:
+		return fmt.Sprintf("zoned_datetime(%s)", quoteString(val.Format(time.RFC3339Nano))), nil
+	case []any:
+		items := make([]string, 0, len(val))
+		for _, e := range val {
+			item, err := toLiteral(e)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(val))
+		for _, k := range keys {
+			item, err := toLiteral(val[k])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, quoteName(k)+": "+item)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+func formatFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("unsupported float %v", f)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s, nil
+}
+
+func quoteString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func quoteName(name string) string {
+	for i, r := range name {
+		if !isIdentRune(r, i == 0) {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+	}
+	if name == "" {
+		return "``"
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package nebulagraph5
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderParams(t *testing.T) {
+	stmt, err := renderParams("MATCH (v) WHERE id(v) == $id AND v.name == $name RETURN v", map[string]any{
+		"id":   int64(1),
+		"name": `a"b`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `MATCH (v) WHERE id(v) == 1 AND v.name == "a\"b" RETURN v`, stmt)
+
+	// the parameters in quotes or not given are kept.
+	stmt, err = renderParams(`RETURN "$id", $other, $id`, map[string]any{"id": 1.5})
+	assert.NoError(t, err)
+	assert.Equal(t, `RETURN "$id", $other, 1.5`, stmt)
+
+	stmt, err = renderParams("RETURN $l, $m, $t, $b, $n", map[string]any{
+		"l": []any{int64(1), "a"},
+		"m": map[string]any{"b": 2.0, "a b": nil},
+		"t": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"b": true,
+		"n": nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `RETURN [1, "a"], {`+"`a b`"+`: NULL, b: 2.0}, zoned_datetime("2024-01-02T03:04:05Z"), true, NULL`, stmt)
+
+	_, err = renderParams("RETURN $f", map[string]any{"f": struct{}{}})
+	assert.Error(t, err)
+
+	stmt, err = renderParams("RETURN $a, $b", map[string]any{"a": uint(1), "b": uint64(math.MaxInt64)})
+	assert.NoError(t, err)
+	assert.Equal(t, "RETURN 1, 9223372036854775807", stmt)
+	_, err = renderParams("RETURN $a", map[string]any{"a": uint64(math.MaxUint64)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+package nebulagraph5
+
+import (
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+	"github.com/vesoft-inc/nebula-go/v5/pkg/types"
+)
+
+// toJSValue converts the nebula value to the value which could be used in js.
+func toJSValue(v types.Value) (any, error) {
+	if v == nil || v.IsNull() {
+		return nil, nil
+	}
+	switch v.GetType() {
+	case types.ValueTypeBool:
+		b, err := v.AsBool()
+		return bool(b), err
+	case types.ValueTypeInt8:
+		i, err := v.AsInt8()
+		return int64(i), err
+	case types.ValueTypeInt16:
+		i, err := v.AsInt16()
+		return int64(i), err
+	case types.ValueTypeInt32:
+		i, err := v.AsInt32()
+		return int64(i), err
+	case types.ValueTypeInt64:
+		i, err := v.AsInt64()
+		return int64(i), err
+	case types.ValueTypeUInt8:
+		i, err := v.AsUInt8()
+		return uint64(i), err
+	case types.ValueTypeUInt16:
+		i, err := v.AsUInt16()
+		return uint64(i), err
+	case types.ValueTypeUInt32:
+		i, err := v.AsUInt32()
+		return uint64(i), err
+	case types.ValueTypeUInt64:
+		i, err := v.AsUInt64()
+		return uint64(i), err
+	case types.ValueTypeFloat:
+		f, err := v.AsFloat()
+		return float64(f), err
+	case types.ValueTypeDouble:
+		f, err := v.AsDouble()
+		return float64(f), err
+	case types.ValueTypeString:
+		s, err := v.AsString()
+		return string(s), err
+	case types.ValueTypeList:
+		l, err := v.AsList()
+		if err != nil {
+			return nil, err
+		}
+		return toJSList(l.GetValues())
+	case types.ValueTypeSet:
+		s, err := v.AsSet()
+		if err != nil {
+			return nil, err
+		}
+		return toJSList(s.GetValues())
+	case types.ValueTypeRecord:
+		r, err := v.AsRecord()
+		if err != nil {
+			return nil, err
+		}
+		return toJSProps(r.GetValues())
+	case types.ValueTypeMap:
+		m, err := v.AsMap()
+		if err != nil {
+			return nil, err
+		}
+		props := make(map[string]any, len(m.GetValues()))
+		for k, e := range m.GetValues() {
+			je, err := toJSValue(e)
+			if err != nil {
+				return nil, err
+			}
+			props[k.String()] = je
+		}
+		return props, nil
+	case types.ValueTypeNode:
+		n, err := v.AsNode()
+		if err != nil {
+			return nil, err
+		}
+		return toJSNode(n)
+	case types.ValueTypeEdge:
+		e, err := v.AsEdge()
+		if err != nil {
+			return nil, err
+		}
+		return toJSEdge(e)
+	case types.ValueTypePath:
+		p, err := v.AsPath()
+		if err != nil {
+			return nil, err
+		}
+		return toJSPath(p)
+	case types.ValueTypeDate:
+		d, err := v.AsDate()
+		if err != nil {
+			return nil, err
+		}
+		return common.NewDate(d.GetYear(), d.GetMonth(), d.GetDay()), nil
+	case types.ValueTypeLocalTime:
+		t, err := v.AsLocalTime()
+		if err != nil {
+			return nil, err
+		}
+		return common.NewTime(t.GetHour(), t.GetMinute(), t.GetSec(), t.GetMicrosec()), nil
+	case types.ValueTypeZonedTime:
+		t, err := v.AsZonedTime()
+		if err != nil {
+			return nil, err
+		}
+		jt := common.NewTime(t.GetHour(), t.GetMinute(), t.GetSec(), t.GetMicrosec())
+		jt["offset"] = t.GetOffset()
+		return jt, nil
+	case types.ValueTypeLocalDateTime:
+		dt, err := v.AsLocalDatetime()
+		if err != nil {
+			return nil, err
+		}
+		return common.NewDateTime(dt.GetYear(), dt.GetMonth(), dt.GetDay(),
+			dt.GetHour(), dt.GetMinute(), dt.GetSec(), dt.GetMicrosec()), nil
+	case types.ValueTypeZonedDateTime:
+		dt, err := v.AsZonedDatetime()
+		if err != nil {
+			return nil, err
+		}
+		jdt := common.NewDateTime(dt.GetYear(), dt.GetMonth(), dt.GetDay(),
+			dt.GetHour(), dt.GetMinute(), dt.GetSec(), dt.GetMicrosec())
+		jdt["offset"] = dt.GetOffset()
+		return jdt, nil
+	case types.ValueTypeEmbeddingVector:
+		ev, err := v.AsEmbeddingVector()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, len(ev.GetValues()))
+		for _, f := range ev.GetValues() {
+			list = append(list, float64(f))
+		}
+		return list, nil
+	default:
+		// duration, decimal and geography
+		return v.String(), nil
+	}
+}
+
+func toJSList(values []types.Value) ([]any, error) {
+	list := make([]any, 0, len(values))
+	for _, v := range values {
+		jv, err := toJSValue(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, jv)
+	}
+	return list, nil
+}
+
+func toJSProps(props map[string]types.Value) (map[string]any, error) {
+	m := make(map[string]any, len(props))
+	for k, v := range props {
+		jv, err := toJSValue(v)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = jv
+	}
+	return m, nil
+}
+
+// toJSNode converts the node, the properties are the same for all the labels of the node.
+func toJSNode(n types.Node) (map[string]any, error) {
+	props, err := toJSProps(n.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	labels := n.GetLabels()
+	labelProps := make(map[string]map[string]any, len(labels))
+	for _, l := range labels {
+		labelProps[l] = props
+	}
+	return common.NewVertex(int64(n.GetId()), labels, labelProps), nil
+}
+
+func toJSEdge(e types.Edge) (map[string]any, error) {
+	props, err := toJSProps(e.GetProperties())
+	if err != nil {
+		return nil, err
+	}
+	return common.NewEdge(int64(e.GetSrcId()), int64(e.GetDstId()), e.GetType(), int64(e.GetRank()), props), nil
+}
+
+// toJSPath converts the path, whose values are nodes and edges one after another.
+func toJSPath(p types.Path) (map[string]any, error) {
+	var (
+		nodes         = make([]any, 0)
+		relationships = make([]any, 0)
+	)
+	for _, v := range p.GetValues() {
+		jv, err := toJSValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if v.GetType() == types.ValueTypeEdge {
+			relationships = append(relationships, jv)
+		} else {
+			nodes = append(nodes, jv)
+		}
+	}
+	return common.NewPath(nodes, relationships), nil
+}
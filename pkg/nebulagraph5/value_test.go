@@ -0,0 +1,216 @@
+package nebulagraph5
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/k6-plugin/pkg/common"
+	"github.com/vesoft-inc/nebula-go/v5/pkg/types"
+)
+
+// fakeValue is the value of typ, the values of nebula-go are not exported.
+type fakeValue struct {
+	types.EmptyValue
+	typ types.ValueType
+	v   any
+}
+
+func val(typ types.ValueType, v any) *fakeValue {
+	return &fakeValue{typ: typ, v: v}
+}
+
+func (f *fakeValue) String() string                  { return fmt.Sprint(f.v) }
+func (f *fakeValue) GetType() types.ValueType        { return f.typ }
+func (f *fakeValue) IsNull() bool                    { return f.typ == types.ValueTypeNull }
+func (f *fakeValue) AsBool() (types.Bool, error)     { return f.v.(types.Bool), nil }
+func (f *fakeValue) AsInt8() (types.Int8, error)     { return f.v.(types.Int8), nil }
+func (f *fakeValue) AsInt64() (types.Int64, error)   { return f.v.(types.Int64), nil }
+func (f *fakeValue) AsUInt32() (types.UInt32, error) { return f.v.(types.UInt32), nil }
+func (f *fakeValue) AsFloat() (types.Float, error)   { return f.v.(types.Float), nil }
+func (f *fakeValue) AsDouble() (types.Double, error) { return f.v.(types.Double), nil }
+func (f *fakeValue) AsString() (types.String, error) { return f.v.(types.String), nil }
+func (f *fakeValue) AsList() (types.List, error)     { return f.v.(types.List), nil }
+func (f *fakeValue) AsSet() (types.Set, error)       { return f.v.(types.Set), nil }
+func (f *fakeValue) AsRecord() (types.Record, error) { return f.v.(types.Record), nil }
+func (f *fakeValue) AsMap() (types.Map, error)       { return f.v.(types.Map), nil }
+func (f *fakeValue) AsNode() (types.Node, error)     { return f.v.(types.Node), nil }
+func (f *fakeValue) AsEdge() (types.Edge, error)     { return f.v.(types.Edge), nil }
+func (f *fakeValue) AsPath() (types.Path, error)     { return f.v.(types.Path), nil }
+func (f *fakeValue) AsDate() (types.Date, error)     { return f.v.(types.Date), nil }
+func (f *fakeValue) AsLocalTime() (types.LocalTime, error) {
+	return f.v.(types.LocalTime), nil
+}
+func (f *fakeValue) AsZonedTime() (types.ZonedTime, error) {
+	return f.v.(types.ZonedTime), nil
+}
+func (f *fakeValue) AsLocalDatetime() (types.LocalDatetime, error) {
+	return f.v.(types.LocalDatetime), nil
+}
+func (f *fakeValue) AsZonedDatetime() (types.ZonedDatetime, error) {
+	return f.v.(types.ZonedDatetime), nil
+}
+func (f *fakeValue) AsEmbeddingVector() (types.EmbeddingVector, error) {
+	return f.v.(types.EmbeddingVector), nil
+}
+
+// fakeList is the list, set and path.
+type fakeList []types.Value
+
+func (l fakeList) String() string           { return fmt.Sprint([]types.Value(l)) }
+func (l fakeList) GetValues() []types.Value { return l }
+func (l fakeList) Size() int                { return len(l) }
+
+type fakeRecord map[string]types.Value
+
+func (r fakeRecord) String() string                    { return fmt.Sprint(map[string]types.Value(r)) }
+func (r fakeRecord) GetValues() map[string]types.Value { return r }
+
+type fakeMap map[types.Value]types.Value
+
+func (m fakeMap) String() string                         { return fmt.Sprint(map[types.Value]types.Value(m)) }
+func (m fakeMap) GetValues() map[types.Value]types.Value { return m }
+
+type fakeNode struct {
+	id     int
+	labels []string
+	props  fakeRecord
+}
+
+func (n *fakeNode) String() string                        { return fmt.Sprint(n.id) }
+func (n *fakeNode) GetProperties() map[string]types.Value { return n.props }
+func (n *fakeNode) GetGraph() string                      { return "g" }
+func (n *fakeNode) GetType() string                       { return "Node" }
+func (n *fakeNode) GetLabels() []string                   { return n.labels }
+func (n *fakeNode) GetId() int                            { return n.id }
+
+type fakeEdge struct {
+	src, dst, rank int
+	typ            string
+	props          fakeRecord
+}
+
+func (e *fakeEdge) String() string                        { return fmt.Sprintf("%d->%d", e.src, e.dst) }
+func (e *fakeEdge) GetProperties() map[string]types.Value { return e.props }
+func (e *fakeEdge) GetSrcId() int                         { return e.src }
+func (e *fakeEdge) GetDstId() int                         { return e.dst }
+func (e *fakeEdge) GetGraph() string                      { return "g" }
+func (e *fakeEdge) GetType() string                       { return e.typ }
+func (e *fakeEdge) GetLabels() []string                   { return []string{e.typ} }
+func (e *fakeEdge) GetRank() int                          { return e.rank }
+func (e *fakeEdge) IsDirected() bool                      { return true }
+
+// fakeTime is all the temporal values, the date, the time and the datetime with or without the offset.
+type fakeTime struct {
+	year, month, day, hour, minute, sec, microsec, offset int
+}
+
+func (t *fakeTime) String() string   { return "" }
+func (t *fakeTime) GetYear() int     { return t.year }
+func (t *fakeTime) GetMonth() int    { return t.month }
+func (t *fakeTime) GetDay() int      { return t.day }
+func (t *fakeTime) GetHour() int     { return t.hour }
+func (t *fakeTime) GetMinute() int   { return t.minute }
+func (t *fakeTime) GetSec() int      { return t.sec }
+func (t *fakeTime) GetMicrosec() int { return t.microsec }
+func (t *fakeTime) GetOffset() int   { return t.offset }
+func (t *fakeTime) Time() *time.Time { return nil }
+
+type fakeVector []float32
+
+func (v fakeVector) String() string       { return fmt.Sprint([]float32(v)) }
+func (v fakeVector) GetValues() []float32 { return v }
+
+func TestToJSValue(t *testing.T) {
+	name := val(types.ValueTypeString, types.String("Tom"))
+	tom := val(types.ValueTypeNode, &fakeNode{
+		id:     1,
+		labels: []string{"Person", "Player"},
+		props:  fakeRecord{"name": name},
+	})
+	bob := val(types.ValueTypeNode, &fakeNode{id: 2, labels: []string{"Person"}, props: fakeRecord{}})
+	knows := val(types.ValueTypeEdge, &fakeEdge{
+		src:   1,
+		dst:   2,
+		rank:  3,
+		typ:   "KNOWS",
+		props: fakeRecord{"since": val(types.ValueTypeInt64, types.Int64(2020))},
+	})
+	tomProps := map[string]any{"name": "Tom"}
+	jsTom := common.NewVertex(int64(1), []string{"Person", "Player"},
+		map[string]map[string]any{"Person": tomProps, "Player": tomProps})
+	jsBob := common.NewVertex(int64(2), []string{"Person"}, map[string]map[string]any{"Person": {}})
+	jsKnows := common.NewEdge(int64(1), int64(2), "KNOWS", 3, map[string]any{"since": int64(2020)})
+	zonedTime := common.NewTime(4, 5, 6, 7)
+	zonedTime["offset"] = 28800
+	zonedDatetime := common.NewDateTime(2024, 1, 2, 3, 4, 5, 6)
+	zonedDatetime["offset"] = -3600
+
+	cases := []struct {
+		name     string
+		value    types.Value
+		expected any
+	}{
+		{"nil", nil, nil},
+		{"null", val(types.ValueTypeNull, nil), nil},
+		{"bool", val(types.ValueTypeBool, types.Bool(true)), true},
+		{"int8", val(types.ValueTypeInt8, types.Int8(-8)), int64(-8)},
+		{"uint32", val(types.ValueTypeUInt32, types.UInt32(32)), uint64(32)},
+		{"float", val(types.ValueTypeFloat, types.Float(0.5)), float64(0.5)},
+		{"double", val(types.ValueTypeDouble, types.Double(1.5)), float64(1.5)},
+		{"string", name, "Tom"},
+		{
+			"list",
+			val(types.ValueTypeList, fakeList{name, val(types.ValueTypeNull, nil), val(types.ValueTypeInt64, types.Int64(1))}),
+			[]any{"Tom", nil, int64(1)},
+		},
+		{"set", val(types.ValueTypeSet, fakeList{name}), []any{"Tom"}},
+		{"record", val(types.ValueTypeRecord, fakeRecord{"name": name}), map[string]any{"name": "Tom"}},
+		{
+			"map",
+			val(types.ValueTypeMap, fakeMap{
+				val(types.ValueTypeInt64, types.Int64(1)):     name,
+				val(types.ValueTypeString, types.String("b")): val(types.ValueTypeList, fakeList{}),
+			}),
+			map[string]any{"1": "Tom", "b": []any{}},
+		},
+		{"node", tom, jsTom},
+		{"edge", knows, jsKnows},
+		{
+			"path",
+			val(types.ValueTypePath, fakeList{tom, knows, bob}),
+			common.NewPath([]any{jsTom, jsBob}, []any{jsKnows}),
+		},
+		{"empty path", val(types.ValueTypePath, fakeList{}), common.NewPath([]any{}, []any{})},
+		{"date", val(types.ValueTypeDate, &fakeTime{year: 2024, month: 1, day: 2}), common.NewDate(2024, 1, 2)},
+		{
+			"local time",
+			val(types.ValueTypeLocalTime, &fakeTime{hour: 4, minute: 5, sec: 6, microsec: 7}),
+			common.NewTime(4, 5, 6, 7),
+		},
+		{
+			"zoned time",
+			val(types.ValueTypeZonedTime, &fakeTime{hour: 4, minute: 5, sec: 6, microsec: 7, offset: 28800}),
+			zonedTime,
+		},
+		{
+			"local datetime",
+			val(types.ValueTypeLocalDateTime, &fakeTime{2024, 1, 2, 3, 4, 5, 6, 0}),
+			common.NewDateTime(2024, 1, 2, 3, 4, 5, 6),
+		},
+		{
+			"zoned datetime",
+			val(types.ValueTypeZonedDateTime, &fakeTime{2024, 1, 2, 3, 4, 5, 6, -3600}),
+			zonedDatetime,
+		},
+		{"embedding vector", val(types.ValueTypeEmbeddingVector, fakeVector{0.5, 1}), []any{0.5, 1.0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v, err := toJSValue(c.value)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, v)
+		})
+	}
+}
@@ -37,7 +37,7 @@ type drawData struct {
 	latency      []float32
 }
 
-// #timestamp,vus,requestCount,errorCount,latencyAvg,latencyP90,latencyP95,latencyP99,responseTimeAvg,responseTimeP90,responseTimeP95,responseTimeP99,rowSizePerReq
+// #timestamp,vus,requestCount,errorCount,latencyAvg,latencyP90,latencyP95,latencyP99,responseTimeAvg,responseTimeP90,responseTimeP95,responseTimeP99,rowSizePerReq,name
 func (d *draw) init() error {
 	bs, err := os.OpenFile(d.filePath, os.O_RDONLY, 0644)
 	if err != nil {
@@ -78,6 +78,10 @@ func (d *draw) init() error {
 		if err == io.EOF {
 			break
 		}
+		// only draw the lines of all the requests, skip the ones of the named statements.
+		if len(record) > 13 && record[13] != "" {
+			continue
+		}
 		vu, _ := strconv.Atoi(record[1])
 		rc, _ := strconv.Atoi(record[2])
 		ec, _ := strconv.Atoi(record[3])